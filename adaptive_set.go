@@ -0,0 +1,434 @@
+package goset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+const (
+	// adaptiveGrowThreshold is the number of elements above which an adaptive set moves from a slice to a map.
+	// Below it, a linear scan of the slice is faster than hashing and uses less memory.
+	adaptiveGrowThreshold = 16
+
+	// adaptiveShrinkThreshold is the number of elements below which an adaptive set moves back to a slice.
+	// It is lower than adaptiveGrowThreshold so that a set hovering around the threshold doesn't migrate on every change.
+	adaptiveShrinkThreshold = 8
+)
+
+// unsafeAdaptiveSet is backed by a slice while it is small and by a map once it grows,
+// since linear scans beat hashing for a handful of elements.
+// Migrating in either direction copies every element, which is O(n) but amortized by the gap between the thresholds.
+type unsafeAdaptiveSet[T comparable] struct {
+	small []T
+	large unsafeSimpleSet[T]
+}
+
+// Assert concrete type:unsafeAdaptiveSet adheres to Set interface.
+var _ Set[string] = (*unsafeAdaptiveSet[string])(nil)
+
+func newUnsafeAdaptiveSet[T comparable]() *unsafeAdaptiveSet[T] {
+	return &unsafeAdaptiveSet[T]{}
+}
+
+func (s *unsafeAdaptiveSet[T]) grow() {
+	if s.large != nil || len(s.small) <= adaptiveGrowThreshold {
+		return
+	}
+	s.large = make(unsafeSimpleSet[T], len(s.small))
+	for _, elem := range s.small {
+		s.large[elem] = struct{}{}
+	}
+	s.small = nil
+}
+
+func (s *unsafeAdaptiveSet[T]) shrink() {
+	if s.large == nil || len(s.large) >= adaptiveShrinkThreshold {
+		return
+	}
+	s.small = make([]T, 0, adaptiveGrowThreshold)
+	for elem := range s.large {
+		s.small = append(s.small, elem)
+	}
+	s.large = nil
+}
+
+func (s *unsafeAdaptiveSet[T]) index(v T) int {
+	for i, elem := range s.small {
+		if elem == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *unsafeAdaptiveSet[T]) add(v T) {
+	if s.large != nil {
+		s.large[v] = struct{}{}
+		return
+	}
+	if s.index(v) < 0 {
+		s.small = append(s.small, v)
+		s.grow()
+	}
+}
+
+func (s *unsafeAdaptiveSet[T]) remove(v T) {
+	if s.large != nil {
+		delete(s.large, v)
+		return
+	}
+	if i := s.index(v); i >= 0 {
+		last := len(s.small) - 1
+		s.small[i] = s.small[last]
+		s.small = s.small[:last]
+	}
+}
+
+func (s *unsafeAdaptiveSet[T]) Add(v ...T) bool {
+	prevLen := s.Len()
+	for _, val := range v {
+		s.add(val)
+	}
+	return prevLen != s.Len()
+}
+
+func (s *unsafeAdaptiveSet[T]) AddSet(other Set[T]) bool {
+	prevLen := s.Len()
+	other.Each(func(elem T) bool {
+		s.add(elem)
+		return true
+	})
+	return prevLen != s.Len()
+}
+
+func (s *unsafeAdaptiveSet[T]) WouldAdd(v T) bool {
+	return !s.contains(v)
+}
+
+func (s *unsafeAdaptiveSet[T]) Len() int {
+	if s.large != nil {
+		return len(s.large)
+	}
+	return len(s.small)
+}
+
+func (s *unsafeAdaptiveSet[T]) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+func (s *unsafeAdaptiveSet[T]) Stats() SetStats {
+	if s.large != nil {
+		return SetStats{Len: s.Len(), IsEmpty: s.IsEmpty(), MapBacked: true}
+	}
+	return SetStats{Len: s.Len(), IsEmpty: s.IsEmpty(), Capacity: cap(s.small)}
+}
+
+func (s *unsafeAdaptiveSet[T]) Clear() {
+	s.small = nil
+	s.large = nil
+}
+
+func (s *unsafeAdaptiveSet[T]) Clone() Set[T] {
+	clone := newUnsafeAdaptiveSet[T]()
+	if s.large != nil {
+		clone.large = make(unsafeSimpleSet[T], len(s.large))
+		for elem := range s.large {
+			clone.large[elem] = struct{}{}
+		}
+		return clone
+	}
+	clone.small = append([]T(nil), s.small...)
+	return clone
+}
+
+func (s *unsafeAdaptiveSet[T]) CloneWith(copy func(T) T) Set[T] {
+	clone := newUnsafeAdaptiveSet[T]()
+	s.Each(func(elem T) bool {
+		clone.add(copy(elem))
+		return true
+	})
+	return clone
+}
+
+func (s *unsafeAdaptiveSet[T]) contains(v T) bool {
+	if s.large != nil {
+		return s.large.contains(v)
+	}
+	return s.index(v) >= 0
+}
+
+func (s *unsafeAdaptiveSet[T]) Contains(v ...T) bool {
+	for _, val := range v {
+		if !s.contains(val) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *unsafeAdaptiveSet[T]) ContainsAny(v ...T) bool {
+	for _, val := range v {
+		if s.contains(val) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *unsafeAdaptiveSet[T]) Each(fn func(T) bool) {
+	if s.large != nil {
+		s.large.Each(fn)
+		return
+	}
+	for _, elem := range s.small {
+		if !fn(elem) {
+			break
+		}
+	}
+}
+
+func (s *unsafeAdaptiveSet[T]) EachMutable(fn func(T) (keep bool)) {
+	if s.large != nil {
+		s.large.EachMutable(fn)
+	} else {
+		kept := s.small[:0]
+		for _, elem := range s.small {
+			if fn(elem) {
+				kept = append(kept, elem)
+			}
+		}
+		s.small = kept
+	}
+	s.shrink()
+}
+
+func (s *unsafeAdaptiveSet[T]) EachSnapshot(fn func(T) bool) {
+	eachOf(s.ToSlice(), fn)
+}
+
+func (s *unsafeAdaptiveSet[T]) Filter(pred func(T) bool) Set[T] {
+	filtered := newUnsafeAdaptiveSet[T]()
+	s.Each(func(elem T) bool {
+		if pred(elem) {
+			filtered.add(elem)
+		}
+		return true
+	})
+	return filtered
+}
+
+func (s *unsafeAdaptiveSet[T]) Partition(pred func(T) bool) (Set[T], Set[T]) {
+	matching, rest := newUnsafeAdaptiveSet[T](), newUnsafeAdaptiveSet[T]()
+	s.Each(func(elem T) bool {
+		if pred(elem) {
+			matching.add(elem)
+		} else {
+			rest.add(elem)
+		}
+		return true
+	})
+	return matching, rest
+}
+
+func (s *unsafeAdaptiveSet[T]) Any(pred func(T) bool) bool {
+	return anyOf(s.Each, pred)
+}
+
+func (s *unsafeAdaptiveSet[T]) All(pred func(T) bool) bool {
+	return allOf(s.Each, pred)
+}
+
+func (s *unsafeAdaptiveSet[T]) Diff(other Set[T]) Set[T] {
+	return diffByContains[T](newUnsafeAdaptiveSet[T](), s, other)
+}
+
+func (s *unsafeAdaptiveSet[T]) DiffLen(other Set[T]) int {
+	return diffLenByContains[T](s, other)
+}
+
+func (s *unsafeAdaptiveSet[T]) SymmetricDiff(other Set[T]) Set[T] {
+	return symmetricDiffByContains[T](newUnsafeAdaptiveSet[T](), s, other)
+}
+
+func (s *unsafeAdaptiveSet[T]) Equal(other Set[T]) bool {
+	return equalByContains[T](s, other)
+}
+
+func (s *unsafeAdaptiveSet[T]) Intersect(other Set[T]) Set[T] {
+	return intersectByContains[T](newUnsafeAdaptiveSet[T](), s, other)
+}
+
+func (s *unsafeAdaptiveSet[T]) IntersectLen(other Set[T]) int {
+	return intersectLenByContains[T](s, other)
+}
+
+func (s *unsafeAdaptiveSet[T]) Disjoint(other Set[T]) bool {
+	return disjointByContains[T](s, other)
+}
+
+func (s *unsafeAdaptiveSet[T]) IntersectWith(other Set[T]) {
+	if other == Set[T](s) {
+		return
+	}
+	s.EachMutable(func(elem T) bool {
+		return other.Contains(elem)
+	})
+}
+
+func (s *unsafeAdaptiveSet[T]) IsSubset(other Set[T]) bool {
+	return isSubsetByContains[T](s, other)
+}
+
+func (s *unsafeAdaptiveSet[T]) IsProperSubset(other Set[T]) bool {
+	return s.Len() < other.Len() && s.IsSubset(other)
+}
+
+func (s *unsafeAdaptiveSet[T]) IsSuperset(other Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+func (s *unsafeAdaptiveSet[T]) IsProperSuperset(other Set[T]) bool {
+	return s.Len() > other.Len() && s.IsSuperset(other)
+}
+
+func (s *unsafeAdaptiveSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *unsafeAdaptiveSet[T]) IterContext(ctx context.Context) <-chan T {
+	return iterContext(ctx, s.Len(), s.Each)
+}
+
+func (s *unsafeAdaptiveSet[T]) Iterator() iter.Seq[T] {
+	return s.Each
+}
+
+func (s *unsafeAdaptiveSet[T]) Consume() <-chan T {
+	elems := s.ToSlice()
+	s.Clear()
+	return sliceChan(elems)
+}
+
+func (s *unsafeAdaptiveSet[T]) Pop() (T, bool) {
+	var elem T
+	var ok bool
+	s.Each(func(v T) bool {
+		elem, ok = v, true
+		return false
+	})
+	if ok {
+		s.Remove(elem)
+	}
+	return elem, ok
+}
+
+func (s *unsafeAdaptiveSet[T]) PopN(n int) []T {
+	popped := make([]T, 0, max(min(n, s.Len()), 0))
+	s.Each(func(elem T) bool {
+		if len(popped) >= n {
+			return false
+		}
+		popped = append(popped, elem)
+		return true
+	})
+	s.Remove(popped...)
+	return popped
+}
+
+func (s *unsafeAdaptiveSet[T]) Sample() (T, bool) {
+	return sampleOf(s.Each)
+}
+
+func (s *unsafeAdaptiveSet[T]) SampleN(n int) []T {
+	return sampleNOf(s.Len(), s.Each, n)
+}
+
+func (s *unsafeAdaptiveSet[T]) Remove(v ...T) {
+	for _, val := range v {
+		s.remove(val)
+	}
+	s.shrink()
+}
+
+func (s *unsafeAdaptiveSet[T]) RemoveSet(other Set[T]) {
+	if other == Set[T](s) {
+		s.Clear()
+		return
+	}
+	other.Each(func(elem T) bool {
+		s.remove(elem)
+		return true
+	})
+	s.shrink()
+}
+
+func (s *unsafeAdaptiveSet[T]) RemoveIf(pred func(T) bool) int {
+	return removeIfByEachMutable[T](s, pred)
+}
+
+func (s *unsafeAdaptiveSet[T]) ReplaceAll(items []T) {
+	s.Clear()
+	s.Add(items...)
+}
+
+func (s *unsafeAdaptiveSet[T]) Toggle(v ...T) int {
+	prevLen := s.Len()
+	for _, val := range v {
+		if s.contains(val) {
+			s.remove(val)
+		} else {
+			s.add(val)
+		}
+	}
+	s.shrink()
+	return s.Len() - prevLen
+}
+
+func (s *unsafeAdaptiveSet[T]) Union(other Set[T]) Set[T] {
+	return unionByContains[T](newUnsafeAdaptiveSet[T](), s, other)
+}
+
+func (s *unsafeAdaptiveSet[T]) UnionWith(other Set[T]) bool {
+	return s.AddSet(other)
+}
+
+func (s *unsafeAdaptiveSet[T]) ToSlice() []T {
+	return s.AppendTo(nil)
+}
+
+func (s *unsafeAdaptiveSet[T]) ToSortedSlice(less func(a, b T) bool) []T {
+	return sortedSlice(s.AppendTo(make([]T, 0, s.Len())), less)
+}
+
+func (s *unsafeAdaptiveSet[T]) AppendTo(dst []T) []T {
+	s.Each(func(elem T) bool {
+		dst = append(dst, elem)
+		return true
+	})
+	return dst
+}
+
+func (s *unsafeAdaptiveSet[T]) String() string {
+	var items []string
+	s.Each(func(elem T) bool {
+		items = append(items, fmt.Sprintf("%#v", elem))
+		return true
+	})
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
+
+func (s *unsafeAdaptiveSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.AppendTo(make([]T, 0, s.Len())))
+}
+
+func (s *unsafeAdaptiveSet[T]) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalJSONElems[T](data)
+	if err != nil {
+		return err
+	}
+	s.Add(elems...)
+	return nil
+}
@@ -0,0 +1,44 @@
+package goset
+
+// AggregateLocked folds every element of s into an accumulator, starting from init.
+// Thread-safe sets hold their read lock for the entire fold, so the result is computed from a single
+// consistent snapshot even while other goroutines mutate the set. For thread-unsafe sets it is a plain fold.
+// fn must not mutate s.
+func AggregateLocked[T any, A any](s Set[T], init A, fn func(A, T) A) A {
+	acc := init
+	s.Each(func(elem T) bool {
+		acc = fn(acc, elem)
+		return true
+	})
+	return acc
+}
+
+// Min returns the smallest element of s according to less, and false if s is empty. Of several elements that are
+// equally small, the first one visited is returned, which for unordered sets is not deterministic.
+func Min[T any](s Set[T], less func(a, b T) bool) (T, bool) {
+	var minElem T
+	found := false
+	s.Each(func(elem T) bool {
+		if !found || less(elem, minElem) {
+			minElem, found = elem, true
+		}
+		return true
+	})
+	return minElem, found
+}
+
+// Max returns the largest element of s according to less, and false if s is empty. Ties are resolved as in Min.
+func Max[T any](s Set[T], less func(a, b T) bool) (T, bool) {
+	return Min(s, func(a, b T) bool { return less(b, a) })
+}
+
+// CountBy groups the elements of s by the bucket key returns for each of them and returns the number of elements in
+// every bucket. Buckets no element falls into are absent from the map.
+func CountBy[T any, U comparable](s Set[T], key func(T) U) map[U]int {
+	counts := make(map[U]int)
+	s.Each(func(elem T) bool {
+		counts[key(elem)]++
+		return true
+	})
+	return counts
+}
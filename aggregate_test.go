@@ -0,0 +1,114 @@
+package goset_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestAggregateLocked(t *testing.T) {
+	sum := func(acc, v int) int { return acc + v }
+
+	assert.Equal(t, 15, goset.AggregateLocked(goset.NewThreadUnsafeSet(1, 2, 3, 4, 5), 0, sum))
+	assert.Equal(t, 7, goset.AggregateLocked(goset.NewSet[int](), 7, sum))
+
+	var itemsA, itemsB []int
+	for i := 0; i < 100; i++ {
+		itemsA = append(itemsA, i)
+		itemsB = append(itemsB, i*3)
+	}
+	sumA := goset.AggregateLocked(goset.NewThreadUnsafeSet(itemsA...), 0, sum)
+	sumB := goset.AggregateLocked(goset.NewThreadUnsafeSet(itemsB...), 0, sum)
+
+	set := goset.NewSet(itemsA...)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if i%2 == 0 {
+				set.ReplaceAll(itemsB)
+			} else {
+				set.ReplaceAll(itemsA)
+			}
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		total := goset.AggregateLocked(set, 0, sum)
+		if total != sumA && total != sumB {
+			assert.Fail(t, "sum doesn't match a consistent snapshot", "got %d", total)
+			break
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestMinMax(t *testing.T) {
+	set := goset.NewSet(4, -2, 9, 7)
+	v, ok := goset.Min(set, intLess)
+	assert.True(t, ok)
+	assert.Equal(t, -2, v)
+	v, ok = goset.Max(set, intLess)
+	assert.True(t, ok)
+	assert.Equal(t, 9, v)
+
+	v, ok = goset.Min(goset.NewSortedSet(intLess, 3, 1, 2), intLess)
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	_, ok = goset.Min(goset.NewSet[int](), intLess)
+	assert.False(t, ok)
+	_, ok = goset.Max(goset.NewThreadUnsafeSet[int](), intLess)
+	assert.False(t, ok)
+}
+
+func TestMinMaxTies(t *testing.T) {
+	byImportance := func(a, b *TestType) bool { return a.Importance < b.Importance }
+	items := goset.NewThreadUnsafeResolvingSet(func(item *TestType) int { return item.ID }, nil)
+	items.Add(
+		&TestType{ID: 1, Name: "One", Importance: 1},
+		&TestType{ID: 2, Name: "Two", Importance: 1},
+		&TestType{ID: 3, Name: "Three", Importance: 5},
+		&TestType{ID: 4, Name: "Four", Importance: 5},
+	)
+
+	least, ok := goset.Min[*TestType](items, byImportance)
+	assert.True(t, ok)
+	assert.Equal(t, 1, least.Importance)
+	assert.Contains(t, []int{1, 2}, least.ID)
+
+	most, ok := goset.Max[*TestType](items, byImportance)
+	assert.True(t, ok)
+	assert.Equal(t, 5, most.Importance)
+	assert.Contains(t, []int{3, 4}, most.ID)
+
+	_, ok = goset.Max[*TestType](goset.NewResolvingSet[*TestType, int](func(item *TestType) int { return item.ID }, nil), byImportance)
+	assert.False(t, ok)
+}
+
+func TestCountBy(t *testing.T) {
+	items := goset.NewResolvingSet(func(item *TestType) int { return item.ID }, nil)
+	items.Add(
+		&TestType{ID: 1, Name: "One", Importance: 1},
+		&TestType{ID: 2, Name: "Two", Importance: 3},
+		&TestType{ID: 3, Name: "Three", Importance: 1},
+		&TestType{ID: 4, Name: "Four", Importance: 2},
+		&TestType{ID: 5, Name: "Five", Importance: 1},
+	)
+
+	counts := goset.CountBy[*TestType](items, func(item *TestType) int { return item.Importance })
+	assert.Equal(t, map[int]int{1: 3, 2: 1, 3: 1}, counts)
+
+	assert.Empty(t, goset.CountBy(goset.NewSet[int](), func(v int) bool { return v%2 == 0 }))
+}
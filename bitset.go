@@ -0,0 +1,628 @@
+package goset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// bitSetMinWords is the number of words the bitmap of a bit set may always grow to, whatever its length.
+const bitSetMinWords = 1024
+
+// unsafeBitSet is backed by a bitmap with one bit per non-negative integer, which is far more compact than a map
+// for dense integers. The bitmap grows to hold the elements added, but never beyond bitSetMinWords or two words per
+// element, whichever is larger, so a few huge values can't make it allocate out of proportion to Len. Negative values
+// and values beyond that limit are kept in the sparse map instead; a value is never in both. The length is cached,
+// so Len is O(1). Binary operations between two bit sets work a 64-bit word at a time.
+type unsafeBitSet struct {
+	words  []uint64
+	sparse map[int]struct{}
+	n      int
+}
+
+// Assert concrete type:unsafeBitSet adheres to Set interface.
+var _ Set[int] = (*unsafeBitSet)(nil)
+
+func newUnsafeBitSet(maxHint int) *unsafeBitSet {
+	return &unsafeBitSet{
+		words: make([]uint64, 0, max(maxHint, 0)/64+1),
+	}
+}
+
+// newUnsafeBitSetFromWords returns a bit set owning words.
+func newUnsafeBitSetFromWords(words []uint64) *unsafeBitSet {
+	s := &unsafeBitSet{words: words}
+	s.recount()
+	return s
+}
+
+func (s *unsafeBitSet) recount() {
+	s.n = len(s.sparse)
+	for _, word := range s.words {
+		s.n += bits.OnesCount64(word)
+	}
+}
+
+// inBitmap returns a boolean indicating if v falls within the current bitmap.
+func (s *unsafeBitSet) inBitmap(v int) bool {
+	return v >= 0 && v/64 < len(s.words)
+}
+
+// grow extends the bitmap to the given number of words and moves the sparse values that now fall within it
+// into the bitmap.
+func (s *unsafeBitSet) grow(words int) {
+	if words <= len(s.words) {
+		return
+	}
+	s.words = append(s.words, make([]uint64, words-len(s.words))...)
+	for v := range s.sparse {
+		if s.inBitmap(v) {
+			delete(s.sparse, v)
+			s.words[v/64] |= 1 << (v % 64)
+		}
+	}
+}
+
+func (s *unsafeBitSet) add(v int) bool {
+	if v >= 0 && v/64 >= len(s.words) && v/64 < max(bitSetMinWords, 2*(s.n+1), cap(s.words)) {
+		s.grow(v/64 + 1)
+	}
+	if !s.inBitmap(v) {
+		if _, ok := s.sparse[v]; ok {
+			return false
+		}
+		if s.sparse == nil {
+			s.sparse = make(map[int]struct{})
+		}
+		s.sparse[v] = struct{}{}
+		s.n++
+		return true
+	}
+	i, bit := v/64, uint64(1)<<(v%64)
+	if s.words[i]&bit != 0 {
+		return false
+	}
+	s.words[i] |= bit
+	s.n++
+	return true
+}
+
+func (s *unsafeBitSet) remove(v int) bool {
+	if !s.contains(v) {
+		return false
+	}
+	if s.inBitmap(v) {
+		s.words[v/64] &^= 1 << (v % 64)
+	} else {
+		delete(s.sparse, v)
+	}
+	s.n--
+	return true
+}
+
+func (s *unsafeBitSet) contains(v int) bool {
+	if !s.inBitmap(v) {
+		_, ok := s.sparse[v]
+		return ok
+	}
+	return s.words[v/64]&(1<<(v%64)) != 0
+}
+
+// sparseContainedIn returns the number of sparse values of s that are also in other.
+func (s *unsafeBitSet) sparseContainedIn(other *unsafeBitSet) int {
+	count := 0
+	for v := range s.sparse {
+		if other.contains(v) {
+			count++
+		}
+	}
+	return count
+}
+
+// word returns the i-th word of the bitmap, which is zero beyond its end.
+func (s *unsafeBitSet) word(i int) uint64 {
+	if i < len(s.words) {
+		return s.words[i]
+	}
+	return 0
+}
+
+func (s *unsafeBitSet) Add(v ...int) bool {
+	var ret bool
+	for _, val := range v {
+		if s.add(val) {
+			ret = true
+		}
+	}
+	return ret
+}
+
+func (s *unsafeBitSet) AddSet(other Set[int]) bool {
+	prevLen := s.n
+	if o, ok := other.(*unsafeBitSet); ok {
+		s.grow(len(o.words))
+		for i, word := range o.words {
+			s.words[i] |= word
+		}
+		s.recount()
+		for v := range o.sparse {
+			s.add(v)
+		}
+		return prevLen != s.n
+	}
+	other.Each(func(elem int) bool {
+		s.add(elem)
+		return true
+	})
+	return prevLen != s.n
+}
+
+func (s *unsafeBitSet) WouldAdd(v int) bool {
+	return !s.contains(v)
+}
+
+func (s *unsafeBitSet) Len() int {
+	return s.n
+}
+
+func (s *unsafeBitSet) IsEmpty() bool {
+	return s.n == 0
+}
+
+func (s *unsafeBitSet) Stats() SetStats {
+	return SetStats{Len: s.n, IsEmpty: s.IsEmpty(), Capacity: cap(s.words) * 64}
+}
+
+func (s *unsafeBitSet) Clear() {
+	clear(s.words)
+	s.sparse = nil
+	s.n = 0
+}
+
+func (s *unsafeBitSet) Clone() Set[int] {
+	return &unsafeBitSet{
+		words:  append([]uint64(nil), s.words...),
+		sparse: maps.Clone(s.sparse),
+		n:      s.n,
+	}
+}
+
+func (s *unsafeBitSet) CloneWith(copy func(int) int) Set[int] {
+	clone := newUnsafeBitSet(len(s.words) * 64)
+	s.Each(func(elem int) bool {
+		clone.add(copy(elem))
+		return true
+	})
+	return clone
+}
+
+func (s *unsafeBitSet) Contains(v ...int) bool {
+	for _, val := range v {
+		if !s.contains(val) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *unsafeBitSet) ContainsAny(v ...int) bool {
+	for _, val := range v {
+		if s.contains(val) {
+			return true
+		}
+	}
+	return false
+}
+
+// Each visits the elements in ascending order.
+func (s *unsafeBitSet) Each(fn func(int) bool) {
+	// negative sparse values come before the bitmap and the others after it
+	sparse := slices.Sorted(maps.Keys(s.sparse))
+	for len(sparse) > 0 && sparse[0] < 0 {
+		if !fn(sparse[0]) {
+			return
+		}
+		sparse = sparse[1:]
+	}
+	for i, word := range s.words {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			word &^= 1 << bit
+			if !fn(i*64 + bit) {
+				return
+			}
+		}
+	}
+	eachOf(sparse, fn)
+}
+
+func (s *unsafeBitSet) EachMutable(fn func(int) (keep bool)) {
+	s.Each(func(elem int) bool {
+		if !fn(elem) {
+			s.remove(elem)
+		}
+		return true
+	})
+}
+
+func (s *unsafeBitSet) EachSnapshot(fn func(int) bool) {
+	eachOf(s.ToSlice(), fn)
+}
+
+func (s *unsafeBitSet) Filter(pred func(int) bool) Set[int] {
+	filtered := newUnsafeBitSet(len(s.words) * 64)
+	s.Each(func(elem int) bool {
+		if pred(elem) {
+			filtered.add(elem)
+		}
+		return true
+	})
+	return filtered
+}
+
+func (s *unsafeBitSet) Partition(pred func(int) bool) (Set[int], Set[int]) {
+	matching, rest := newUnsafeBitSet(len(s.words)*64), newUnsafeBitSet(len(s.words)*64)
+	s.Each(func(elem int) bool {
+		if pred(elem) {
+			matching.add(elem)
+		} else {
+			rest.add(elem)
+		}
+		return true
+	})
+	return matching, rest
+}
+
+func (s *unsafeBitSet) Any(pred func(int) bool) bool {
+	return anyOf(s.Each, pred)
+}
+
+func (s *unsafeBitSet) All(pred func(int) bool) bool {
+	return allOf(s.Each, pred)
+}
+
+func (s *unsafeBitSet) Diff(other Set[int]) Set[int] {
+	o, ok := other.(*unsafeBitSet)
+	if !ok {
+		return diffByContains[int](newUnsafeBitSet(0), s, other)
+	}
+	words := make([]uint64, len(s.words))
+	for i, word := range s.words {
+		words[i] = word &^ o.word(i)
+	}
+	diff := newUnsafeBitSetFromWords(words)
+	for v := range o.sparse {
+		diff.remove(v)
+	}
+	for v := range s.sparse {
+		if !o.contains(v) {
+			diff.add(v)
+		}
+	}
+	return diff
+}
+
+func (s *unsafeBitSet) DiffLen(other Set[int]) int {
+	o, ok := other.(*unsafeBitSet)
+	if !ok {
+		return diffLenByContains[int](s, other)
+	}
+	return s.n - s.IntersectLen(o)
+}
+
+func (s *unsafeBitSet) SymmetricDiff(other Set[int]) Set[int] {
+	o, ok := other.(*unsafeBitSet)
+	if !ok {
+		return symmetricDiffByContains[int](newUnsafeBitSet(0), s, other)
+	}
+	words := make([]uint64, max(len(s.words), len(o.words)))
+	for i := range words {
+		words[i] = s.word(i) ^ o.word(i)
+	}
+	// a sparse value of one set may be in the bitmap of the other, where the words above can't see it
+	diff := newUnsafeBitSetFromWords(words)
+	for v := range s.sparse {
+		if o.contains(v) {
+			diff.remove(v)
+		} else {
+			diff.add(v)
+		}
+	}
+	for v := range o.sparse {
+		if s.contains(v) {
+			diff.remove(v)
+		} else {
+			diff.add(v)
+		}
+	}
+	return diff
+}
+
+func (s *unsafeBitSet) Equal(other Set[int]) bool {
+	o, ok := other.(*unsafeBitSet)
+	if !ok {
+		return equalByContains[int](s, other)
+	}
+	return s.n == o.n && s.IsSubset(o)
+}
+
+func (s *unsafeBitSet) Intersect(other Set[int]) Set[int] {
+	o, ok := other.(*unsafeBitSet)
+	if !ok {
+		return intersectByContains[int](newUnsafeBitSet(0), s, other)
+	}
+	words := make([]uint64, min(len(s.words), len(o.words)))
+	for i := range words {
+		words[i] = s.words[i] & o.words[i]
+	}
+	intersection := newUnsafeBitSetFromWords(words)
+	for v := range s.sparse {
+		if o.contains(v) {
+			intersection.add(v)
+		}
+	}
+	for v := range o.sparse {
+		if s.contains(v) {
+			intersection.add(v)
+		}
+	}
+	return intersection
+}
+
+func (s *unsafeBitSet) IntersectLen(other Set[int]) int {
+	o, ok := other.(*unsafeBitSet)
+	if !ok {
+		return intersectLenByContains[int](s, other)
+	}
+	// a value in the sparse maps of both sets is only counted with those of s
+	count := s.sparseContainedIn(o)
+	for v := range o.sparse {
+		if s.inBitmap(v) && s.contains(v) {
+			count++
+		}
+	}
+	for i := range min(len(s.words), len(o.words)) {
+		count += bits.OnesCount64(s.words[i] & o.words[i])
+	}
+	return count
+}
+
+func (s *unsafeBitSet) Disjoint(other Set[int]) bool {
+	o, ok := other.(*unsafeBitSet)
+	if !ok {
+		return disjointByContains[int](s, other)
+	}
+	for i := range min(len(s.words), len(o.words)) {
+		if s.words[i]&o.words[i] != 0 {
+			return false
+		}
+	}
+	return s.sparseContainedIn(o) == 0 && o.sparseContainedIn(s) == 0
+}
+
+func (s *unsafeBitSet) IntersectWith(other Set[int]) {
+	if other == Set[int](s) {
+		return
+	}
+	o, ok := other.(*unsafeBitSet)
+	if !ok {
+		s.EachMutable(func(elem int) bool {
+			return other.Contains(elem)
+		})
+		return
+	}
+	// elements of the bitmap that o keeps in its sparse map are lost by the words below, so they are restored after
+	var kept []int
+	for v := range o.sparse {
+		if s.inBitmap(v) && s.contains(v) {
+			kept = append(kept, v)
+		}
+	}
+	for i := range s.words {
+		s.words[i] &= o.word(i)
+	}
+	for _, v := range kept {
+		s.words[v/64] |= 1 << (v % 64)
+	}
+	for v := range s.sparse {
+		if !o.contains(v) {
+			delete(s.sparse, v)
+		}
+	}
+	s.recount()
+}
+
+func (s *unsafeBitSet) IsSubset(other Set[int]) bool {
+	o, ok := other.(*unsafeBitSet)
+	if !ok {
+		return isSubsetByContains[int](s, other)
+	}
+	for i, word := range s.words {
+		// bits missing from the bitmap of o may still be in its sparse map
+		for missing := word &^ o.word(i); missing != 0; missing &= missing - 1 {
+			if !o.contains(i*64 + bits.TrailingZeros64(missing)) {
+				return false
+			}
+		}
+	}
+	return s.sparseContainedIn(o) == len(s.sparse)
+}
+
+func (s *unsafeBitSet) IsProperSubset(other Set[int]) bool {
+	return s.Len() < other.Len() && s.IsSubset(other)
+}
+
+func (s *unsafeBitSet) IsSuperset(other Set[int]) bool {
+	return other.IsSubset(s)
+}
+
+func (s *unsafeBitSet) IsProperSuperset(other Set[int]) bool {
+	return s.Len() > other.Len() && s.IsSuperset(other)
+}
+
+func (s *unsafeBitSet) Iter() <-chan int {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *unsafeBitSet) IterContext(ctx context.Context) <-chan int {
+	return iterContext(ctx, s.Len(), s.Each)
+}
+
+func (s *unsafeBitSet) Iterator() iter.Seq[int] {
+	return s.Each
+}
+
+func (s *unsafeBitSet) Consume() <-chan int {
+	elems := s.ToSlice()
+	s.Clear()
+	return sliceChan(elems)
+}
+
+// Pop removes the smallest element.
+func (s *unsafeBitSet) Pop() (int, bool) {
+	var elem int
+	ok := false
+	s.Each(func(smallest int) bool {
+		elem, ok = smallest, true
+		return false
+	})
+	if ok {
+		s.remove(elem)
+	}
+	return elem, ok
+}
+
+// PopN removes the n smallest elements.
+func (s *unsafeBitSet) PopN(n int) []int {
+	popped := make([]int, 0, max(min(n, s.Len()), 0))
+	for len(popped) < n {
+		elem, ok := s.Pop()
+		if !ok {
+			break
+		}
+		popped = append(popped, elem)
+	}
+	return popped
+}
+
+func (s *unsafeBitSet) Sample() (int, bool) {
+	return sampleOf(s.Each)
+}
+
+func (s *unsafeBitSet) SampleN(n int) []int {
+	return sampleNOf(s.Len(), s.Each, n)
+}
+
+func (s *unsafeBitSet) Remove(v ...int) {
+	for _, val := range v {
+		s.remove(val)
+	}
+}
+
+func (s *unsafeBitSet) RemoveSet(other Set[int]) {
+	o, ok := other.(*unsafeBitSet)
+	if !ok {
+		other.Each(func(elem int) bool {
+			s.remove(elem)
+			return true
+		})
+		return
+	}
+	for i := range min(len(s.words), len(o.words)) {
+		s.words[i] &^= o.words[i]
+	}
+	s.recount()
+	for v := range o.sparse {
+		s.remove(v)
+	}
+	for v := range s.sparse {
+		if o.contains(v) {
+			s.remove(v)
+		}
+	}
+}
+
+func (s *unsafeBitSet) RemoveIf(pred func(int) bool) int {
+	return removeIfByEachMutable[int](s, pred)
+}
+
+func (s *unsafeBitSet) ReplaceAll(items []int) {
+	s.Clear()
+	s.Add(items...)
+}
+
+func (s *unsafeBitSet) Toggle(v ...int) int {
+	prevLen := s.n
+	for _, val := range v {
+		if !s.remove(val) {
+			s.add(val)
+		}
+	}
+	return s.n - prevLen
+}
+
+func (s *unsafeBitSet) Union(other Set[int]) Set[int] {
+	o, ok := other.(*unsafeBitSet)
+	if !ok {
+		return unionByContains[int](newUnsafeBitSet(len(s.words)*64), s, other)
+	}
+	words := make([]uint64, max(len(s.words), len(o.words)))
+	for i := range words {
+		words[i] = s.word(i) | o.word(i)
+	}
+	union := newUnsafeBitSetFromWords(words)
+	for v := range s.sparse {
+		union.add(v)
+	}
+	for v := range o.sparse {
+		union.add(v)
+	}
+	return union
+}
+
+func (s *unsafeBitSet) UnionWith(other Set[int]) bool {
+	return s.AddSet(other)
+}
+
+func (s *unsafeBitSet) ToSlice() []int {
+	return s.AppendTo(make([]int, 0, s.n))
+}
+
+func (s *unsafeBitSet) ToSortedSlice(less func(a, b int) bool) []int {
+	return sortedSlice(s.ToSlice(), less)
+}
+
+func (s *unsafeBitSet) AppendTo(dst []int) []int {
+	s.Each(func(elem int) bool {
+		dst = append(dst, elem)
+		return true
+	})
+	return dst
+}
+
+func (s *unsafeBitSet) String() string {
+	var items []string
+	s.Each(func(elem int) bool {
+		items = append(items, fmt.Sprintf("%#v", elem))
+		return true
+	})
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
+
+func (s *unsafeBitSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *unsafeBitSet) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalJSONElems[int](data)
+	if err != nil {
+		return err
+	}
+	s.Add(elems...)
+	return nil
+}
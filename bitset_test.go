@@ -0,0 +1,94 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestBitSet(t *testing.T) {
+	set := goset.NewThreadUnsafeBitSet(10, 500, 3, 64, 0)
+	assert.Equal(t, 4, set.Len())
+	assert.True(t, set.Contains(0, 3, 64, 500))
+	assert.False(t, set.Contains(-1))
+	assert.False(t, set.Contains(1_000_000))
+	assert.EqualValues(t, []int{0, 3, 64, 500}, set.ToSlice())
+	assert.Equal(t, "Set{0, 3, 64, 500}", set.String())
+
+	set.Remove(-1, 1_000_000)
+	assert.Equal(t, 4, set.Len())
+
+	v, ok := set.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 0, v)
+	assert.EqualValues(t, []int{3, 64}, set.PopN(2))
+
+	// sets whose bitmaps have grown to different lengths still compare word by word
+	grown := goset.NewThreadUnsafeBitSet(0, 500, 10_000)
+	grown.Remove(10_000)
+	assert.True(t, grown.Equal(set))
+	assert.True(t, set.Equal(grown))
+	assert.True(t, set.IsSubset(grown))
+
+	a := goset.NewThreadUnsafeBitSet(1000, 1, 2, 3, 100, 1000)
+	b := goset.NewThreadUnsafeBitSet(1000, 2, 3, 4, 1000, 5000)
+	assert.EqualValues(t, []int{1, 100}, a.Diff(b).ToSlice())
+	assert.EqualValues(t, []int{2, 3, 1000}, a.Intersect(b).ToSlice())
+	assert.EqualValues(t, []int{1, 2, 3, 4, 100, 1000, 5000}, a.Union(b).ToSlice())
+	assert.EqualValues(t, []int{1, 4, 100, 5000}, a.SymmetricDiff(b).ToSlice())
+	assert.Equal(t, 2, a.DiffLen(b))
+	assert.Equal(t, 3, a.IntersectLen(b))
+
+	a.IntersectWith(b)
+	assert.EqualValues(t, []int{2, 3, 1000}, a.ToSlice())
+	a.RemoveSet(goset.NewThreadUnsafeBitSet(0, 3))
+	assert.EqualValues(t, []int{2, 1000}, a.ToSlice())
+	assert.True(t, a.AddSet(b))
+	assert.EqualValues(t, []int{2, 3, 4, 1000, 5000}, a.ToSlice())
+}
+
+func TestBitSetOutOfRangeValues(t *testing.T) {
+	set := goset.NewThreadUnsafeBitSet(10, 3, 64)
+	assert.True(t, set.WouldAdd(-1))
+	assert.True(t, set.Add(-1, -70, 1<<40))
+	assert.False(t, set.Add(-1))
+	assert.False(t, set.WouldAdd(1<<40))
+	assert.Equal(t, 5, set.Len())
+	assert.True(t, set.Contains(-70, -1, 1<<40))
+	assert.EqualValues(t, []int{-70, -1, 3, 64, 1 << 40}, set.ToSlice())
+	// the huge value is not held in the bitmap
+	assert.Less(t, set.Stats().Capacity, 1<<20)
+
+	other := goset.NewThreadUnsafeBitSet(0, -1, 3, 1<<40, 1<<41)
+	assert.EqualValues(t, []int{-1, 3, 1 << 40}, set.Intersect(other).ToSlice())
+	assert.Equal(t, 3, set.IntersectLen(other))
+	assert.EqualValues(t, []int{-70, 64}, set.Diff(other).ToSlice())
+	assert.Equal(t, 2, set.DiffLen(other))
+	assert.EqualValues(t, []int{-70, 64, 1 << 41}, set.SymmetricDiff(other).ToSlice())
+	assert.EqualValues(t, []int{-70, -1, 3, 64, 1 << 40, 1 << 41}, set.Union(other).ToSlice())
+	assert.False(t, set.Disjoint(other))
+	assert.True(t, set.Intersect(other).IsSubset(other))
+	assert.False(t, set.IsSubset(other))
+
+	// a value in the bitmap of one set can be in the sparse map of the other
+	small := goset.NewThreadUnsafeBitSet(0, 1, 100_000)
+	large := goset.NewThreadUnsafeBitSet(200_000, 100_000)
+	assert.True(t, large.IsSubset(small))
+	assert.True(t, large.Equal(small.Intersect(large)))
+	assert.True(t, small.Intersect(large).Equal(large))
+	assert.EqualValues(t, []int{1}, small.Diff(large).ToSlice())
+	assert.EqualValues(t, []int{1}, small.SymmetricDiff(large).ToSlice())
+	large.IntersectWith(small)
+	assert.EqualValues(t, []int{100_000}, large.ToSlice())
+	small.RemoveSet(large)
+	assert.EqualValues(t, []int{1}, small.ToSlice())
+
+	v, ok := set.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, -70, v)
+
+	assert.NotPanics(t, func() { goset.NewBitSet(10).UnionWith(goset.NewSet(-1)) })
+	assert.True(t, goset.NewBitSet(10).Union(goset.NewSet(-1)).Contains(-1))
+}
@@ -0,0 +1,73 @@
+package goset
+
+import (
+	"hash/maphash"
+	"math"
+	"sync/atomic"
+)
+
+// prefilter answers membership queries without taking a set's lock.
+// It may report false positives but never false negatives.
+type prefilter[T any] interface {
+	add(v ...T)
+	mayContain(v T) bool
+	reset()
+}
+
+type bloomFilter[T comparable] struct {
+	seed   maphash.Seed
+	bits   []atomic.Uint64
+	size   uint64
+	hashes uint64
+}
+
+// Assert concrete type:bloomFilter adheres to prefilter interface.
+var _ prefilter[int] = (*bloomFilter[int])(nil)
+
+func newBloomFilter[T comparable](expectedN int, fpr float64) *bloomFilter[T] {
+	n := math.Max(float64(expectedN), 1)
+	p := math.Min(math.Max(fpr, math.SmallestNonzeroFloat64), 0.5)
+	size := uint64(math.Ceil(-n * math.Log(p) / (math.Ln2 * math.Ln2)))
+	hashes := uint64(math.Max(math.Round(float64(size)/n*math.Ln2), 1))
+	return &bloomFilter[T]{
+		seed:   maphash.MakeSeed(),
+		bits:   make([]atomic.Uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+// positions calls fn with each bit position for v until fn returns false.
+func (f *bloomFilter[T]) positions(v T, fn func(pos uint64) bool) {
+	h := maphash.Comparable(f.seed, v)
+	h1, h2 := h&math.MaxUint32, h>>32|1
+	for i := uint64(0); i < f.hashes; i++ {
+		if !fn((h1 + i*h2) % f.size) {
+			return
+		}
+	}
+}
+
+func (f *bloomFilter[T]) add(v ...T) {
+	for _, val := range v {
+		f.positions(val, func(pos uint64) bool {
+			f.bits[pos/64].Or(1 << (pos % 64))
+			return true
+		})
+	}
+}
+
+func (f *bloomFilter[T]) mayContain(v T) bool {
+	found := true
+	f.positions(v, func(pos uint64) bool {
+		found = f.bits[pos/64].Load()&(1<<(pos%64)) != 0
+		return found
+	})
+	return found
+}
+
+func (f *bloomFilter[T]) reset() {
+	for i := range f.bits {
+		f.bits[i].Store(0)
+	}
+}
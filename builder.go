@@ -0,0 +1,54 @@
+package goset
+
+// SetBuilder builds a thread-safe set incrementally, much like strings.Builder builds a string.
+// Its backing map is presized from a capacity hint and handed over to the set returned by Build
+// without being copied. A SetBuilder is not thread-safe. The zero value is ready to use.
+type SetBuilder[T comparable] struct {
+	set      *unsafeSimpleSet[T]
+	capacity int
+}
+
+// NewSetBuilder returns an empty SetBuilder whose sets are presized to hold capacity elements.
+func NewSetBuilder[T comparable](capacity int) *SetBuilder[T] {
+	return &SetBuilder[T]{capacity: capacity}
+}
+
+func (b *SetBuilder[T]) init() {
+	if b.set == nil {
+		b.set = newUnsafeSimpleSetWithCapacity[T](b.capacity)
+	}
+}
+
+// Add adds one or more elements to the set being built.
+func (b *SetBuilder[T]) Add(v ...T) {
+	b.init()
+	b.set.add(v...)
+}
+
+// Remove removes the given elements from the set being built.
+func (b *SetBuilder[T]) Remove(v ...T) {
+	if b.set != nil {
+		b.set.Remove(v...)
+	}
+}
+
+// Len returns the number of elements in the set being built.
+func (b *SetBuilder[T]) Len() int {
+	if b.set == nil {
+		return 0
+	}
+	return b.set.Len()
+}
+
+// Build returns the built set and resets the builder, so that it can be reused to build another set.
+func (b *SetBuilder[T]) Build() Set[T] {
+	b.init()
+	set := &safeSet[T]{set: b.set}
+	b.set = nil
+	return set
+}
+
+// Reset discards the elements added since the last Build.
+func (b *SetBuilder[T]) Reset() {
+	b.set = nil
+}
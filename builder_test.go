@@ -0,0 +1,33 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestSetBuilder(t *testing.T) {
+	builder := goset.NewSetBuilder[int](4)
+	builder.Add(1, 2, 3)
+	builder.Add(3, 4)
+	builder.Remove(2)
+	assert.Equal(t, 3, builder.Len())
+
+	set := builder.Build()
+	assert.True(t, goset.NewSet(1, 3, 4).Equal(set))
+	assert.Zero(t, builder.Len())
+
+	// the built set is independent of the builder once built
+	builder.Add(5)
+	assert.False(t, set.Contains(5))
+
+	builder.Reset()
+	builder.Add(6)
+	assert.True(t, goset.NewSet(6).Equal(builder.Build()))
+
+	var zero goset.SetBuilder[string]
+	zero.Remove("a")
+	assert.Zero(t, zero.Build().Len())
+}
@@ -0,0 +1,241 @@
+package goset
+
+import (
+	"context"
+	"iter"
+)
+
+type cloningSet[T any, U comparable] struct {
+	Set[T]
+	keyGetter KeyGetter[T, U]
+	clone     func(T) T
+}
+
+// Assert concrete type:cloningSet adheres to Set interface.
+var _ Set[int] = (*cloningSet[int, string])(nil)
+
+// NewCloningSet returns a thread-safe set that stores a copy, made with clone, of every element added to it
+// and hands out copies of its elements, so that mutating an element outside the set can't change
+// the stored element or its key. Elements are unique by the key returned by keyGetter and the first element
+// added for a key is kept. clone must return a deep copy that has the same key as the original.
+func NewCloningSet[T any, U comparable](keyGetter KeyGetter[T, U], clone func(T) T) Set[T] {
+	return &cloningSet[T, U]{
+		Set:       newSafeResolvingSet(keyGetter, nil),
+		keyGetter: keyGetter,
+		clone:     clone,
+	}
+}
+
+func (s *cloningSet[T, U]) empty() *cloningSet[T, U] {
+	return NewCloningSet(s.keyGetter, s.clone).(*cloningSet[T, U])
+}
+
+func (s *cloningSet[T, U]) cloneAll(v []T) []T {
+	clones := make([]T, len(v))
+	for i, val := range v {
+		clones[i] = s.clone(val)
+	}
+	return clones
+}
+
+func (s *cloningSet[T, U]) Add(v ...T) bool {
+	return s.Set.Add(s.cloneAll(v)...)
+}
+
+func (s *cloningSet[T, U]) AddSet(other Set[T]) bool {
+	return s.Set.Add(s.cloneAll(other.ToSlice())...)
+}
+
+func (s *cloningSet[T, U]) Clone() Set[T] {
+	clone := s.empty()
+	clone.Add(s.Set.ToSlice()...)
+	return clone
+}
+
+func (s *cloningSet[T, U]) CloneWith(copy func(T) T) Set[T] {
+	clone := s.empty()
+	s.Set.Each(func(elem T) bool {
+		clone.Add(copy(elem))
+		return true
+	})
+	return clone
+}
+
+func (s *cloningSet[T, U]) Each(fn func(T) bool) {
+	s.Set.Each(func(elem T) bool {
+		return fn(s.clone(elem))
+	})
+}
+
+func (s *cloningSet[T, U]) EachMutable(fn func(T) (keep bool)) {
+	s.Set.EachMutable(func(elem T) bool {
+		return fn(s.clone(elem))
+	})
+}
+
+func (s *cloningSet[T, U]) EachSnapshot(fn func(T) bool) {
+	eachOf(s.ToSlice(), fn)
+}
+
+func (s *cloningSet[T, U]) Filter(pred func(T) bool) Set[T] {
+	filtered := s.empty()
+	s.Set.Each(func(elem T) bool {
+		if pred(s.clone(elem)) {
+			filtered.Set.Add(s.clone(elem))
+		}
+		return true
+	})
+	return filtered
+}
+
+func (s *cloningSet[T, U]) Partition(pred func(T) bool) (Set[T], Set[T]) {
+	matching, rest := s.empty(), s.empty()
+	s.Set.Each(func(elem T) bool {
+		if pred(s.clone(elem)) {
+			matching.Set.Add(s.clone(elem))
+		} else {
+			rest.Set.Add(s.clone(elem))
+		}
+		return true
+	})
+	return matching, rest
+}
+
+func (s *cloningSet[T, U]) Any(pred func(T) bool) bool {
+	return anyOf(s.Each, pred)
+}
+
+func (s *cloningSet[T, U]) All(pred func(T) bool) bool {
+	return allOf(s.Each, pred)
+}
+
+func (s *cloningSet[T, U]) Diff(other Set[T]) Set[T] {
+	return diffByContains[T](s.empty(), s, other)
+}
+
+func (s *cloningSet[T, U]) DiffLen(other Set[T]) int {
+	return diffLenByContains[T](s, other)
+}
+
+func (s *cloningSet[T, U]) SymmetricDiff(other Set[T]) Set[T] {
+	return symmetricDiffByContains[T](s.empty(), s, other)
+}
+
+func (s *cloningSet[T, U]) Equal(other Set[T]) bool {
+	return equalByContains[T](s, other)
+}
+
+func (s *cloningSet[T, U]) Intersect(other Set[T]) Set[T] {
+	return intersectByContains[T](s.empty(), s, other)
+}
+
+func (s *cloningSet[T, U]) IntersectLen(other Set[T]) int {
+	return intersectLenByContains[T](s, other)
+}
+
+func (s *cloningSet[T, U]) Disjoint(other Set[T]) bool {
+	return disjointByContains[T](s, other)
+}
+
+func (s *cloningSet[T, U]) IntersectWith(other Set[T]) {
+	if other == Set[T](s) {
+		return
+	}
+	s.Set.IntersectWith(other)
+}
+
+func (s *cloningSet[T, U]) IsSubset(other Set[T]) bool {
+	return isSubsetByContains[T](s, other)
+}
+
+func (s *cloningSet[T, U]) IsProperSubset(other Set[T]) bool {
+	return s.Len() < other.Len() && s.IsSubset(other)
+}
+
+func (s *cloningSet[T, U]) IsSuperset(other Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+func (s *cloningSet[T, U]) IsProperSuperset(other Set[T]) bool {
+	return s.Len() > other.Len() && s.IsSuperset(other)
+}
+
+func (s *cloningSet[T, U]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *cloningSet[T, U]) IterContext(ctx context.Context) <-chan T {
+	return iterContext(ctx, s.Len(), s.Each)
+}
+
+func (s *cloningSet[T, U]) Iterator() iter.Seq[T] {
+	return s.Each
+}
+
+func (s *cloningSet[T, U]) Consume() <-chan T {
+	var elems []T
+	for elem := range s.Set.Consume() {
+		elems = append(elems, s.clone(elem))
+	}
+	return sliceChan(elems)
+}
+
+func (s *cloningSet[T, U]) Pop() (T, bool) {
+	elem, ok := s.Set.Pop()
+	if ok {
+		elem = s.clone(elem)
+	}
+	return elem, ok
+}
+
+func (s *cloningSet[T, U]) RemoveSet(other Set[T]) {
+	s.Set.Remove(other.ToSlice()...)
+}
+
+func (s *cloningSet[T, U]) RemoveIf(pred func(T) bool) int {
+	return removeIfByEachMutable[T](s, pred)
+}
+
+func (s *cloningSet[T, U]) PopN(n int) []T {
+	return s.cloneAll(s.Set.PopN(n))
+}
+
+func (s *cloningSet[T, U]) Sample() (T, bool) {
+	elem, ok := s.Set.Sample()
+	if ok {
+		elem = s.clone(elem)
+	}
+	return elem, ok
+}
+
+func (s *cloningSet[T, U]) SampleN(n int) []T {
+	return s.cloneAll(s.Set.SampleN(n))
+}
+
+func (s *cloningSet[T, U]) ReplaceAll(items []T) {
+	s.Set.ReplaceAll(s.cloneAll(items))
+}
+
+func (s *cloningSet[T, U]) Toggle(v ...T) int {
+	return s.Set.Toggle(s.cloneAll(v)...)
+}
+
+func (s *cloningSet[T, U]) Union(other Set[T]) Set[T] {
+	return unionByContains[T](s.empty(), s, other)
+}
+
+func (s *cloningSet[T, U]) UnionWith(other Set[T]) bool {
+	return s.AddSet(other)
+}
+
+func (s *cloningSet[T, U]) ToSlice() []T {
+	return s.cloneAll(s.Set.ToSlice())
+}
+
+func (s *cloningSet[T, U]) ToSortedSlice(less func(a, b T) bool) []T {
+	return sortedSlice(s.ToSlice(), less)
+}
+
+func (s *cloningSet[T, U]) AppendTo(dst []T) []T {
+	return append(dst, s.ToSlice()...)
+}
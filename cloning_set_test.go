@@ -0,0 +1,63 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func cloneTestType(item *TestType) *TestType {
+	clone := *item
+	return &clone
+}
+
+func TestCloningSet(t *testing.T) {
+	newSet := func() goset.Set[*TestType] {
+		return goset.NewCloningSet(func(item *TestType) int { return item.ID }, cloneTestType)
+	}
+
+	item := &TestType{ID: 1, Name: "One", Importance: 1}
+	set := newSet()
+	assert.True(t, set.Add(item, &TestType{ID: 2, Name: "Two", Importance: 1}))
+
+	item.Name = "Mutated"
+	item.Importance = 100
+	assert.True(t, set.Contains(item))
+	stored := set.ToSlice()
+	sortTestItems(stored)
+	assert.Equal(t, "One", stored[0].Name)
+	assert.Equal(t, 1, stored[0].Importance)
+
+	stored[0].Name = "Mutated"
+	for elem := range set.Iter() {
+		assert.NotEqual(t, "Mutated", elem.Name)
+		elem.Name = "Mutated"
+	}
+	set.Each(func(elem *TestType) bool {
+		assert.NotEqual(t, "Mutated", elem.Name)
+		elem.Name = "Mutated"
+		return true
+	})
+	for _, elem := range set.ToSlice() {
+		assert.NotEqual(t, "Mutated", elem.Name)
+	}
+
+	clone := set.Clone()
+	assert.True(t, clone.Equal(set))
+	union := set.Union(newSet())
+	assert.True(t, union.Equal(set))
+	for _, elem := range union.ToSlice() {
+		assert.NotEqual(t, "Mutated", elem.Name)
+	}
+
+	popped, ok := set.Pop()
+	assert.True(t, ok)
+	popped.Name = "Mutated"
+	assert.Equal(t, 1, set.Len())
+	assert.True(t, clone.Contains(popped))
+	for _, elem := range clone.ToSlice() {
+		assert.NotEqual(t, "Mutated", elem.Name)
+	}
+}
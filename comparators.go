@@ -0,0 +1,37 @@
+package goset
+
+import "cmp"
+
+// Comparator reports whether a sorts before b. It is the less function taken by NewSortedSet, ToSortedSlice,
+// Min, Max and MaxResolver, so the combinators below can build any of their arguments.
+type Comparator[T any] func(a, b T) bool
+
+// Reverse returns a Comparator that sorts in the opposite order of c.
+func Reverse[T any](c Comparator[T]) Comparator[T] {
+	return func(a, b T) bool {
+		return c(b, a)
+	}
+}
+
+// ByKey returns a Comparator that sorts by ascending key, computing the key of each element with key.
+func ByKey[T any, U cmp.Ordered](key func(T) U) Comparator[T] {
+	return func(a, b T) bool {
+		return cmp.Less(key(a), key(b))
+	}
+}
+
+// Chain returns a Comparator that sorts by the first of cs, breaking ties with each following one in turn.
+// Elements tied by every comparator are equal, as are all elements when cs is empty.
+func Chain[T any](cs ...Comparator[T]) Comparator[T] {
+	return func(a, b T) bool {
+		for _, c := range cs {
+			switch {
+			case c(a, b):
+				return true
+			case c(b, a):
+				return false
+			}
+		}
+		return false
+	}
+}
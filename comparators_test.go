@@ -0,0 +1,48 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestComparators(t *testing.T) {
+	items := []*TestType{
+		{ID: 3, Name: "Three", Importance: 1},
+		{ID: 1, Name: "One", Importance: 2},
+		{ID: 4, Name: "Four", Importance: 1},
+		{ID: 2, Name: "Two", Importance: 3},
+	}
+	byImportance := goset.ByKey(func(item *TestType) int { return item.Importance })
+	byID := goset.ByKey(func(item *TestType) int { return item.ID })
+	ids := func(items []*TestType) []int {
+		var ids []int
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	set := goset.NewThreadUnsafeSet(items...)
+	assert.EqualValues(t, []int{1, 2, 3, 4}, ids(set.ToSortedSlice(byID)))
+	assert.EqualValues(t, []int{4, 3, 2, 1}, ids(set.ToSortedSlice(goset.Reverse(byID))))
+
+	// importance alone ties 3 and 4, so the ID breaks the tie
+	chained := goset.Chain(byImportance, byID)
+	assert.EqualValues(t, []int{3, 4, 1, 2}, ids(set.ToSortedSlice(chained)))
+	assert.EqualValues(t, []int{2, 1, 4, 3}, ids(set.ToSortedSlice(goset.Reverse(chained))))
+	assert.EqualValues(t, []int{2, 1, 3, 4}, ids(set.ToSortedSlice(goset.Chain(goset.Reverse(byImportance), byID))))
+
+	// a sorted set ordered by importance alone collapses elements of equal importance
+	sorted := goset.NewSortedSet(byImportance, items...)
+	assert.EqualValues(t, []int{3, 1, 2}, ids(sorted.ToSlice()))
+	assert.Equal(t, 4, goset.NewSortedSet(chained, items...).Len())
+
+	least, ok := goset.Min(set, goset.Reverse(byImportance))
+	assert.True(t, ok)
+	assert.Equal(t, 2, least.ID)
+
+	assert.False(t, goset.Chain[*TestType]()(items[0], items[1]))
+}
@@ -0,0 +1,95 @@
+package goset
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// EqualSets returns a boolean indicating if a and b have the same length and every element of a is in b.
+// It only uses the Set interface, so it compares any two implementations alike, whether thread-safe or not.
+// Membership is decided by b.Contains, so a resolving b matches elements by key.
+func EqualSets[T any](a, b Set[T]) bool {
+	return equalByContains(a, b)
+}
+
+// EqualNormalized returns a boolean indicating if a and b are equal after applying normalize to every element
+// of both sets. Neither set is modified.
+func EqualNormalized[T comparable](a, b Set[T], normalize func(T) T) bool {
+	normalizedA := newUnsafeSimpleSet[T]()
+	a.Each(func(elem T) bool {
+		normalizedA.add(normalize(elem))
+		return true
+	})
+	normalizedB := newUnsafeSimpleSet[T]()
+	b.Each(func(elem T) bool {
+		normalizedB.add(normalize(elem))
+		return true
+	})
+	return normalizedA.Equal(normalizedB)
+}
+
+// ChangeLog returns a human-readable list of the changes from old to current: a "+ " line for every added element
+// followed by a "- " line for every removed element, each group in ascending order, so the output is deterministic.
+func ChangeLog[T cmp.Ordered](old, current Set[T]) []string {
+	var added, removed []T
+	current.Each(func(elem T) bool {
+		if !old.Contains(elem) {
+			added = append(added, elem)
+		}
+		return true
+	})
+	old.Each(func(elem T) bool {
+		if !current.Contains(elem) {
+			removed = append(removed, elem)
+		}
+		return true
+	})
+	slices.Sort(added)
+	slices.Sort(removed)
+
+	lines := make([]string, 0, len(added)+len(removed))
+	for _, elem := range added {
+		lines = append(lines, fmt.Sprintf("+ %v", elem))
+	}
+	for _, elem := range removed {
+		lines = append(lines, fmt.Sprintf("- %v", elem))
+	}
+	return lines
+}
+
+// IsSubsetSampled is a probabilistic prefilter for IsSubset. It checks a random sample of sampleSize elements
+// of s for membership in other and returns whether s is a subset of other along with whether that answer is sure.
+// A sampled element missing from other proves s is not a subset, so the result is (false, true).
+// If every sampled element is in other the result is (true, false), unless the sample covered all of s,
+// in which case it is (true, true). It never reports a sure subset that isn't one.
+// Each element is checked as soon as it is sampled, so a non-subset is rejected at the first miss, but a sample
+// without a miss may walk all of s, which is O(len(s)) like IsSubset.
+func IsSubsetSampled[T comparable](s, other Set[T], sampleSize int) (subset bool, sure bool) {
+	size := s.Len()
+	if size > other.Len() {
+		return false, true
+	}
+	if sampleSize <= 0 {
+		return true, size == 0
+	}
+
+	subset = true
+	sampled, seen := 0, 0
+	s.Each(func(elem T) bool {
+		// selection sampling: picking each element with probability needed/remaining yields a uniform sample
+		remaining := max(size-seen, 1)
+		seen++
+		if rand.IntN(remaining) >= sampleSize-sampled {
+			return true
+		}
+		sampled++
+		subset = other.Contains(elem)
+		return subset && sampled < sampleSize
+	})
+	if !subset {
+		return false, true
+	}
+	return true, sampled == size
+}
@@ -0,0 +1,161 @@
+package goset_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestEqualSets(t *testing.T) {
+	safe := goset.NewSet(1, 2, 3)
+	assert.True(t, goset.EqualSets(safe, goset.NewThreadUnsafeSet(3, 2, 1)))
+	assert.True(t, goset.EqualSets(goset.NewThreadUnsafeSet(3, 2, 1), safe))
+	assert.True(t, goset.EqualSets(safe, goset.NewOrderedSet(2, 3, 1)))
+	assert.True(t, goset.EqualSets(goset.NewOrderedSet(2, 3, 1), safe))
+	assert.True(t, goset.EqualSets(safe, safe))
+
+	assert.False(t, goset.EqualSets(safe, goset.NewThreadUnsafeSet(1, 2, 4)))
+	assert.False(t, goset.EqualSets(safe, goset.NewOrderedSet(1, 2)))
+	assert.False(t, goset.EqualSets(goset.NewOrderedSet(1, 2, 3, 4), safe))
+
+	assert.True(t, goset.EqualSets(goset.NewSet[int](), goset.NewThreadUnsafeOrderedSet[int]()))
+}
+
+func TestEqualNormalized(t *testing.T) {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.TrimSpace(s))
+	}
+
+	setA := goset.NewSet("Apple", " banana", "CHERRY ")
+	setB := goset.NewThreadUnsafeSet("apple", "Banana", "cherry")
+	assert.False(t, setA.Equal(setB))
+	assert.True(t, goset.EqualNormalized(setA, setB, normalize))
+	assert.True(t, goset.EqualNormalized(setB, setA, normalize))
+	assert.True(t, setA.Contains("Apple", " banana", "CHERRY "))
+
+	setC := goset.NewSet("apple", "APPLE ", "banana", "cherry")
+	assert.True(t, goset.EqualNormalized(setA, setC, normalize))
+
+	setD := goset.NewSet("apple", "banana", "date")
+	assert.False(t, goset.EqualNormalized(setA, setD, normalize))
+}
+
+func TestChangeLog(t *testing.T) {
+	old := goset.NewSet("beta", "delta", "alpha", "gamma")
+	current := goset.NewSet("gamma", "zeta", "epsilon", "alpha")
+
+	expected := []string{
+		"+ epsilon",
+		"+ zeta",
+		"- beta",
+		"- delta",
+	}
+	assert.Equal(t, expected, goset.ChangeLog(old, current))
+	assert.Empty(t, goset.ChangeLog(old, old))
+	assert.Equal(t, []string{"+ 1", "+ 2"}, goset.ChangeLog(goset.NewSet[int](), goset.NewSet(2, 1)))
+}
+
+func TestIsSubsetSampled(t *testing.T) {
+	other := goset.NewSet[int]()
+	subset := goset.NewSet[int]()
+	notSubset := goset.NewSet[int]()
+	for i := 0; i < 1000; i++ {
+		other.Add(i)
+		subset.Add(i)
+		if i%2 == 0 {
+			notSubset.Add(i)
+		} else {
+			notSubset.Add(-i)
+		}
+	}
+
+	isSubset, sure := goset.IsSubsetSampled(subset, other, 10)
+	assert.True(t, isSubset)
+	assert.False(t, sure)
+
+	isSubset, sure = goset.IsSubsetSampled(subset, other, 1000)
+	assert.True(t, isSubset)
+	assert.True(t, sure)
+
+	// half the elements are missing, so 64 samples reject it with overwhelming probability
+	isSubset, sure = goset.IsSubsetSampled(notSubset, other, 64)
+	assert.False(t, isSubset)
+	assert.True(t, sure)
+
+	// a single missing element can slip past the sample, but is never reported as a sure subset
+	almostSubset := subset.Clone()
+	almostSubset.Remove(0)
+	almostSubset.Add(-1)
+	for i := 0; i < 100; i++ {
+		isSubset, sure = goset.IsSubsetSampled(almostSubset, other, 10)
+		assert.False(t, isSubset && sure)
+	}
+
+	isSubset, sure = goset.IsSubsetSampled(other, goset.NewSet(1), 10)
+	assert.False(t, isSubset)
+	assert.True(t, sure)
+}
+
+// copyTrackingSet records whether the elements of the set it wraps were copied out in bulk.
+type copyTrackingSet struct {
+	goset.Set[int]
+	copied bool
+}
+
+func (s *copyTrackingSet) ToSlice() []int {
+	s.copied = true
+	return s.Set.ToSlice()
+}
+
+func (s *copyTrackingSet) AppendTo(dst []int) []int {
+	s.copied = true
+	return s.Set.AppendTo(dst)
+}
+
+func (s *copyTrackingSet) EachSnapshot(fn func(int) bool) {
+	s.copied = true
+	s.Set.EachSnapshot(fn)
+}
+
+// eachCountingSet counts the elements visited through Each.
+type eachCountingSet struct {
+	goset.Set[int]
+	visited int
+}
+
+func (s *eachCountingSet) Each(fn func(int) bool) {
+	s.Set.Each(func(elem int) bool {
+		s.visited++
+		return fn(elem)
+	})
+}
+
+func TestIsSubsetSampledStopsAtFirstMiss(t *testing.T) {
+	other := goset.NewSet[int]()
+	set := &eachCountingSet{Set: goset.NewSet[int]()}
+	for i := 0; i < 10_000; i++ {
+		other.Add(i)
+		set.Add(-i - 1)
+	}
+
+	isSubset, sure := goset.IsSubsetSampled[int](set, other, 100)
+	assert.False(t, isSubset)
+	assert.True(t, sure)
+	// the first sampled element already misses, and one in a hundred elements is sampled
+	assert.Less(t, set.visited, 5_000)
+}
+
+func TestIsSubsetSampledDoesNotCopySet(t *testing.T) {
+	set := &copyTrackingSet{Set: goset.NewSet[int]()}
+	for i := 0; i < 10_000; i++ {
+		set.Add(i)
+	}
+
+	isSubset, sure := goset.IsSubsetSampled[int](set, set.Clone(), 10)
+	assert.True(t, isSubset)
+	assert.False(t, sure)
+	assert.False(t, set.copied)
+}
@@ -0,0 +1,85 @@
+package goset
+
+// GreedyHittingSet returns a small set of elements that intersects every set in the given family.
+// It repeatedly picks the element contained in the largest number of sets that are not yet hit,
+// until every set is hit. Empty sets cannot be hit and are ignored.
+//
+// The result is an approximation: finding a minimum hitting set is NP-hard, and the greedy choice
+// is only guaranteed to be within a logarithmic factor of the optimum.
+func GreedyHittingSet[T comparable](family ...Set[T]) Set[T] {
+	hitting := NewSet[T]()
+
+	var unhit []Set[T]
+	for _, set := range family {
+		if !set.IsEmpty() {
+			unhit = append(unhit, set)
+		}
+	}
+
+	for len(unhit) > 0 {
+		counts := make(map[T]int)
+		var best T
+		bestCount := 0
+		for _, set := range unhit {
+			set.Each(func(elem T) bool {
+				counts[elem]++
+				if counts[elem] > bestCount {
+					best = elem
+					bestCount = counts[elem]
+				}
+				return true
+			})
+		}
+
+		hitting.Add(best)
+		remaining := unhit[:0]
+		for _, set := range unhit {
+			if !set.Contains(best) {
+				remaining = append(remaining, set)
+			}
+		}
+		unhit = remaining
+	}
+	return hitting
+}
+
+// EstimateCoverSize returns the number of candidate sets the greedy set cover algorithm needs to cover every
+// element of universe, or -1 if the candidates together don't cover it. The greedy algorithm repeatedly picks
+// the candidate covering the most uncovered elements, so like GreedyHittingSet this is an approximation
+// of the minimum cover size, which is NP-hard to find.
+func EstimateCoverSize[T comparable](universe Set[T], candidates ...Set[T]) int {
+	uncovered := newUnsafeSimpleSet[T]()
+	universe.Each(func(elem T) bool {
+		uncovered.add(elem)
+		return true
+	})
+
+	size := 0
+	for !uncovered.IsEmpty() {
+		var best Set[T]
+		bestCount := 0
+		for _, candidate := range candidates {
+			count := 0
+			candidate.Each(func(elem T) bool {
+				if uncovered.contains(elem) {
+					count++
+				}
+				return true
+			})
+			if count > bestCount {
+				best = candidate
+				bestCount = count
+			}
+		}
+		if best == nil {
+			return -1
+		}
+
+		best.Each(func(elem T) bool {
+			uncovered.Remove(elem)
+			return true
+		})
+		size++
+	}
+	return size
+}
@@ -0,0 +1,47 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestGreedyHittingSet(t *testing.T) {
+	family := []goset.Set[int]{
+		goset.NewSet(1, 2, 3),
+		goset.NewSet(3, 4),
+		goset.NewSet(4, 5, 6),
+		goset.NewSet(6, 7),
+		goset.NewSet(8),
+		goset.NewSet[int](),
+	}
+
+	hitting := goset.GreedyHittingSet(family...)
+	for _, set := range family {
+		if set.Len() == 0 {
+			continue
+		}
+		assert.False(t, set.Intersect(hitting).Len() == 0, "set %s is not hit by %s", set, hitting)
+	}
+	assert.LessOrEqual(t, hitting.Len(), 4)
+
+	assert.Zero(t, goset.GreedyHittingSet[int]().Len())
+}
+
+func TestEstimateCoverSize(t *testing.T) {
+	universe := goset.NewSet(1, 2, 3, 4, 5, 6, 7, 8, 9)
+	candidates := []goset.Set[int]{
+		goset.NewSet(1, 2, 3, 4),
+		goset.NewSet(4, 5),
+		goset.NewSet(5, 6, 7),
+		goset.NewSet(1, 8),
+		goset.NewSet(8, 9),
+	}
+
+	assert.Equal(t, 3, goset.EstimateCoverSize(universe, candidates...))
+	assert.Equal(t, -1, goset.EstimateCoverSize(goset.NewSet(1, 10), candidates...))
+	assert.Equal(t, 0, goset.EstimateCoverSize(goset.NewSet[int](), candidates...))
+	assert.Equal(t, -1, goset.EstimateCoverSize(universe))
+}
@@ -0,0 +1,27 @@
+package goset
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ToDOT returns a Graphviz DOT digraph of a set of pairs, where each pair becomes an edge from First to Second.
+// Node names are the quoted fmt.Sprint representations of the pair elements and edges are sorted
+// so the output is deterministic.
+func ToDOT[A comparable, B comparable](s Set[Pair[A, B]]) string {
+	var edges []string
+	s.Each(func(p Pair[A, B]) bool {
+		edges = append(edges, fmt.Sprintf("\t%q -> %q;\n", fmt.Sprint(p.First), fmt.Sprint(p.Second)))
+		return true
+	})
+	sort.Strings(edges)
+
+	var sb strings.Builder
+	sb.WriteString("digraph {\n")
+	for _, edge := range edges {
+		sb.WriteString(edge)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -0,0 +1,26 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestToDOT(t *testing.T) {
+	edges := goset.NewSet(
+		goset.Pair[string, string]{First: "b", Second: "c"},
+		goset.Pair[string, string]{First: "a", Second: "b"},
+		goset.Pair[string, string]{First: "a", Second: "c"},
+	)
+
+	expected := "digraph {\n" +
+		"\t\"a\" -> \"b\";\n" +
+		"\t\"a\" -> \"c\";\n" +
+		"\t\"b\" -> \"c\";\n" +
+		"}\n"
+	assert.Equal(t, expected, goset.ToDOT(edges))
+
+	assert.Equal(t, "digraph {\n}\n", goset.ToDOT(goset.NewSet[goset.Pair[int, int]]()))
+}
@@ -0,0 +1,254 @@
+package goset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// setFormatVersion is written at the start of every binary serialized set so that
+// future changes to the format can be detected when the set is loaded. JSON is the exception, see unmarshalJSONElems.
+const setFormatVersion = 1
+
+const (
+	deltaAdd    byte = '+'
+	deltaRemove byte = '-'
+)
+
+// checkFormatVersion returns an error if a serialized set was written with an unsupported format version.
+func checkFormatVersion(version byte) error {
+	if version != setFormatVersion {
+		return fmt.Errorf("unsupported set format version %d", version)
+	}
+	return nil
+}
+
+// EncodeDelta writes the changes that turn old into current to w, encoding each element with enc.
+// The delta starts with the format version, followed by one record per added or removed element:
+// a '+' or '-' byte, the uvarint length of the encoded element and the encoded element itself.
+func EncodeDelta[T comparable](w io.Writer, old, current Set[T], enc func(T) []byte) error {
+	bw := bufio.NewWriter(w)
+	if err := bw.WriteByte(setFormatVersion); err != nil {
+		return err
+	}
+
+	var err error
+	writeRecord := func(op byte, elem T) bool {
+		data := enc(elem)
+		record := make([]byte, 0, 1+binary.MaxVarintLen64+len(data))
+		record = append(record, op)
+		record = binary.AppendUvarint(record, uint64(len(data)))
+		record = append(record, data...)
+		_, err = bw.Write(record)
+		return err == nil
+	}
+	current.Each(func(elem T) bool {
+		return old.Contains(elem) || writeRecord(deltaAdd, elem)
+	})
+	if err != nil {
+		return err
+	}
+	old.Each(func(elem T) bool {
+		return current.Contains(elem) || writeRecord(deltaRemove, elem)
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ApplyDelta reads a delta written by EncodeDelta from r, decoding each element with dec, and applies it to s.
+// The whole delta is read before s is changed, so s is left untouched if the delta is malformed.
+func ApplyDelta[T comparable](r io.Reader, s Set[T], dec func([]byte) T) error {
+	br := bufio.NewReader(r)
+	version, err := br.ReadByte()
+	if err != nil {
+		return fmt.Errorf("reading set delta version: %w", err)
+	}
+	if err := checkFormatVersion(version); err != nil {
+		return err
+	}
+
+	var added, removed []T
+	for {
+		op, err := br.ReadByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("reading set delta: %w", err)
+		}
+		if op != deltaAdd && op != deltaRemove {
+			return fmt.Errorf("invalid set delta operation %q", op)
+		}
+
+		size, err := binary.ReadUvarint(br)
+		if err != nil {
+			return fmt.Errorf("reading set delta: %w", noEOF(err))
+		}
+		if size > math.MaxInt64 {
+			return fmt.Errorf("reading set delta: element size %d is too large", size)
+		}
+		// the size comes from the input, so the buffer only grows as data actually arrives
+		var data bytes.Buffer
+		if _, err := io.CopyN(&data, br, int64(size)); err != nil {
+			return fmt.Errorf("reading set delta: %w", noEOF(err))
+		}
+
+		if op == deltaAdd {
+			added = append(added, dec(data.Bytes()))
+		} else {
+			removed = append(removed, dec(data.Bytes()))
+		}
+	}
+
+	s.Remove(removed...)
+	s.Add(added...)
+	return nil
+}
+
+// noEOF turns io.EOF into io.ErrUnexpectedEOF for reads in the middle of a record.
+func noEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// unmarshalJSONElems decodes the elements of a set marshaled to JSON. Version 1 of the JSON format is a bare array
+// with no version header, so that sets stay interchangeable with plain arrays in config files and API payloads.
+// A later version would be an object holding its version under "v", which is rejected with the versioning error.
+func unmarshalJSONElems[T any](data []byte) ([]T, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var header struct {
+			V *int `json:"v"`
+		}
+		if err := json.Unmarshal(trimmed, &header); err == nil && header.V != nil && *header.V != setFormatVersion {
+			return nil, fmt.Errorf("unsupported set format version %d", *header.V)
+		}
+	}
+	var elems []T
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return nil, err
+	}
+	return elems, nil
+}
+
+// gobEncodeElems returns the format version followed by the gob encoding of elems.
+func gobEncodeElems[T any](elems []T) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(setFormatVersion)
+	if err := gob.NewEncoder(&buf).Encode(elems); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gobDecodeElems decodes the elements of a set encoded by gobEncodeElems.
+func gobDecodeElems[T any](data []byte) ([]T, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("reading gob encoded set version: %w", io.ErrUnexpectedEOF)
+	}
+	if err := checkFormatVersion(data[0]); err != nil {
+		return nil, err
+	}
+	var elems []T
+	if err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(&elems); err != nil {
+		return nil, fmt.Errorf("decoding gob encoded set: %w", err)
+	}
+	return elems, nil
+}
+
+// marshalBinaryElems returns the compact binary encoding of elems: the format version, the uvarint count of elements
+// and then each element. Signed integers are written as varints, unsigned integers as uvarints and strings
+// as their uvarint length followed by their bytes. Elements of any other kind are rejected.
+func marshalBinaryElems[T any](elems []T) ([]byte, error) {
+	data := []byte{setFormatVersion}
+	data = binary.AppendUvarint(data, uint64(len(elems)))
+	for _, elem := range elems {
+		v := reflect.ValueOf(&elem).Elem()
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			data = binary.AppendVarint(data, v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			data = binary.AppendUvarint(data, v.Uint())
+		case reflect.String:
+			data = binary.AppendUvarint(data, uint64(v.Len()))
+			data = append(data, v.String()...)
+		default:
+			return nil, fmt.Errorf("unsupported set element type %s for binary encoding", v.Type())
+		}
+	}
+	return data, nil
+}
+
+// unmarshalBinaryElems decodes the elements of a set encoded by marshalBinaryElems.
+func unmarshalBinaryElems[T any](data []byte) ([]T, error) {
+	r := bytes.NewReader(data)
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("reading binary set version: %w", noEOF(err))
+	}
+	if err := checkFormatVersion(version); err != nil {
+		return nil, err
+	}
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading binary set: %w", noEOF(err))
+	}
+	// every element takes at least one byte, so a larger count can only come from corrupt input
+	if count > uint64(r.Len()) {
+		return nil, fmt.Errorf("reading binary set: %d elements do not fit in %d bytes", count, r.Len())
+	}
+
+	elems := make([]T, count)
+	for i := range elems {
+		v := reflect.ValueOf(&elems[i]).Elem()
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := binary.ReadVarint(r)
+			if err != nil {
+				return nil, fmt.Errorf("reading binary set: %w", noEOF(err))
+			}
+			if v.OverflowInt(n) {
+				return nil, fmt.Errorf("reading binary set: %d overflows %s", n, v.Type())
+			}
+			v.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			n, err := binary.ReadUvarint(r)
+			if err != nil {
+				return nil, fmt.Errorf("reading binary set: %w", noEOF(err))
+			}
+			if v.OverflowUint(n) {
+				return nil, fmt.Errorf("reading binary set: %d overflows %s", n, v.Type())
+			}
+			v.SetUint(n)
+		case reflect.String:
+			size, err := binary.ReadUvarint(r)
+			if err != nil {
+				return nil, fmt.Errorf("reading binary set: %w", noEOF(err))
+			}
+			if size > uint64(r.Len()) {
+				return nil, fmt.Errorf("reading binary set: %w", io.ErrUnexpectedEOF)
+			}
+			buf := make([]byte, size)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return nil, fmt.Errorf("reading binary set: %w", noEOF(err))
+			}
+			v.SetString(string(buf))
+		default:
+			return nil, fmt.Errorf("unsupported set element type %s for binary encoding", v.Type())
+		}
+	}
+	if r.Len() > 0 {
+		return nil, fmt.Errorf("reading binary set: %d bytes of trailing data", r.Len())
+	}
+	return elems, nil
+}
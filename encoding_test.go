@@ -0,0 +1,295 @@
+package goset_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sfodje/goset"
+)
+
+func encodeString(s string) []byte { return []byte(s) }
+
+func decodeString(b []byte) string { return string(b) }
+
+func TestDelta(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		old := goset.NewSet("a", "b", "c")
+		target := goset.NewSet("b", "c", "d", "e")
+
+		var buf bytes.Buffer
+		require.NoError(t, goset.EncodeDelta(&buf, old, target, encodeString))
+
+		replica := old.Clone()
+		require.NoError(t, goset.ApplyDelta(&buf, replica, decodeString))
+		assert.True(t, replica.Equal(target))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		set := goset.NewSet("a")
+
+		var buf bytes.Buffer
+		require.NoError(t, goset.EncodeDelta(&buf, set, set, encodeString))
+		assert.Equal(t, 1, buf.Len())
+		require.NoError(t, goset.ApplyDelta(&buf, set, decodeString))
+		assert.True(t, set.Equal(goset.NewSet("a")))
+	})
+
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, goset.EncodeDelta(&buf, goset.NewSet[string](), goset.NewSet("a"), encodeString))
+		payload := buf.Bytes()
+		payload[0] = 2
+
+		set := goset.NewSet[string]()
+		err := goset.ApplyDelta(bytes.NewReader(payload), set, decodeString)
+		assert.EqualError(t, err, "unsupported set format version 2")
+		assert.Zero(t, set.Len())
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, goset.EncodeDelta(&buf, goset.NewSet[string](), goset.NewSet("abc", "def"), encodeString))
+		payload := buf.Bytes()
+
+		set := goset.NewSet[string]()
+		err := goset.ApplyDelta(bytes.NewReader(payload[:len(payload)-1]), set, decodeString)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Zero(t, set.Len())
+
+		err = goset.ApplyDelta(bytes.NewReader([]byte{1, '*'}), set, decodeString)
+		assert.Error(t, err)
+	})
+
+	t.Run("CorruptLength", func(t *testing.T) {
+		set := goset.NewSet("b")
+		tooLarge := []byte{1, '+', 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}
+		err := goset.ApplyDelta(bytes.NewReader(tooLarge), set, decodeString)
+		assert.EqualError(t, err, "reading set delta: element size 18446744073709551615 is too large")
+
+		// a valid record adding "a", followed by one claiming far more bytes than the input holds
+		truncated := []byte{1, '+', 1, 'a', '-', 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x40}
+		err = goset.ApplyDelta(bytes.NewReader(truncated), set, decodeString)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.True(t, set.Equal(goset.NewSet("b")))
+	})
+}
+
+func TestUnsupportedFormatVersion(t *testing.T) {
+	const want = "unsupported set format version 2"
+
+	gobData, err := goset.NewSet(1).(gob.GobEncoder).GobEncode()
+	require.NoError(t, err)
+	gobData[0] = 2
+	assert.EqualError(t, goset.NewSet[int]().(gob.GobDecoder).GobDecode(gobData), want)
+
+	binaryData, err := goset.NewSet(1).(encoding.BinaryMarshaler).MarshalBinary()
+	require.NoError(t, err)
+	binaryData[0] = 2
+	assert.EqualError(t, goset.NewSet[int]().(encoding.BinaryUnmarshaler).UnmarshalBinary(binaryData), want)
+
+	var delta bytes.Buffer
+	require.NoError(t, goset.EncodeDelta(&delta, goset.NewSet[int](), goset.NewSet(1), func(v int) []byte { return []byte{byte(v)} }))
+	deltaData := delta.Bytes()
+	deltaData[0] = 2
+	assert.EqualError(t, goset.ApplyDelta(bytes.NewReader(deltaData), goset.NewSet[int](), func(b []byte) int { return int(b[0]) }), want)
+
+	// version 1 of the JSON format is a bare array, and a later version is an object holding its version
+	identity := func(v int) int { return v }
+	for _, set := range []goset.Set[int]{
+		goset.NewSet[int](),
+		goset.NewThreadUnsafeSet[int](),
+		goset.NewAdaptiveSet[int](),
+		goset.NewBitSet(0),
+		goset.NewOrderedSet[int](),
+		goset.NewSortedSet(func(a, b int) bool { return a < b }),
+		goset.NewResolvingSet[int, int](identity, nil),
+		goset.NewThreadUnsafeResolvingSet[int, int](identity, nil),
+	} {
+		assert.EqualError(t, json.Unmarshal([]byte(` {"v": 2, "elems": [1]}`), set), want)
+		assert.Zero(t, set.Len())
+		require.NoError(t, json.Unmarshal([]byte(`[1]`), set))
+		assert.True(t, set.Equal(goset.NewSet(1)))
+	}
+}
+
+func TestJSON(t *testing.T) {
+	factories := []struct {
+		name   string
+		newSet func(v ...string) goset.Set[string]
+	}{
+		{name: "UnsafeSimpleSet", newSet: func(v ...string) goset.Set[string] { return goset.NewThreadUnsafeSet(v...) }},
+		{name: "SafeSimpleSet", newSet: func(v ...string) goset.Set[string] { return goset.NewSet(v...) }},
+		{name: "AdaptiveSet", newSet: func(v ...string) goset.Set[string] { return goset.NewAdaptiveSet(v...) }},
+	}
+
+	for _, f := range factories {
+		t.Run(f.name, func(t *testing.T) {
+			set := f.newSet("a", "b")
+			data, err := json.Marshal(set)
+			require.NoError(t, err)
+
+			var elems []string
+			require.NoError(t, json.Unmarshal(data, &elems))
+			sort.Strings(elems)
+			assert.Equal(t, []string{"a", "b"}, elems)
+
+			decoded := f.newSet()
+			require.NoError(t, json.Unmarshal(data, decoded))
+			assert.True(t, set.Equal(decoded))
+
+			data, err = json.Marshal(f.newSet())
+			require.NoError(t, err)
+			assert.Equal(t, "[]", string(data))
+
+			assert.Error(t, json.Unmarshal([]byte(`{"a": 1}`), decoded))
+		})
+	}
+
+	t.Run("InStruct", func(t *testing.T) {
+		type config struct {
+			Tags goset.Set[string] `json:"tags"`
+		}
+		data, err := json.Marshal(config{Tags: goset.NewSet("x")})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"tags": ["x"]}`, string(data))
+
+		decoded := config{Tags: goset.NewSet[string]()}
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.True(t, decoded.Tags.Equal(goset.NewSet("x")))
+	})
+
+	resolvingFactories := []struct {
+		name   string
+		newSet func() goset.ResolvingSet[*TestType, int]
+	}{
+		{name: "UnsafeResolvingSet", newSet: func() goset.ResolvingSet[*TestType, int] {
+			return goset.NewThreadUnsafeResolvingSet(func(item *TestType) int { return item.ID }, keepMostImportant)
+		}},
+		{name: "SafeResolvingSet", newSet: func() goset.ResolvingSet[*TestType, int] {
+			return goset.NewResolvingSet(func(item *TestType) int { return item.ID }, keepMostImportant)
+		}},
+	}
+	for _, f := range resolvingFactories {
+		t.Run(f.name, func(t *testing.T) {
+			set := f.newSet()
+			set.Add(testItems...)
+			data, err := json.Marshal(set)
+			require.NoError(t, err)
+
+			decoded := f.newSet()
+			require.NoError(t, json.Unmarshal(data, decoded))
+			assert.True(t, set.Equal(decoded))
+
+			actualItems := decoded.ToSlice()
+			sortTestItems(actualItems)
+			assert.EqualValues(t, []*TestType{testItems[5], testItems[3], testItems[2]}, actualItems)
+
+			// decoded elements still go through the resolver
+			require.NoError(t, json.Unmarshal([]byte(`[{"ID": 1, "Name": "One", "Importance": 0}]`), decoded))
+			assert.Contains(t, decoded.ToSlice(), testItems[5])
+		})
+	}
+}
+
+func lessImportant(a, b *TestType) bool {
+	return a.Importance < b.Importance
+}
+
+func keepMostImportant(foundItem, newItem *TestType) (*TestType, bool) {
+	if newItem.Importance > foundItem.Importance {
+		return newItem, true
+	}
+	return foundItem, false
+}
+
+func TestGob(t *testing.T) {
+	factories := []struct {
+		name   string
+		newSet func(v ...int) goset.Set[int]
+	}{
+		{name: "UnsafeSimpleSet", newSet: func(v ...int) goset.Set[int] { return goset.NewThreadUnsafeSet(v...) }},
+		{name: "SafeSimpleSet", newSet: func(v ...int) goset.Set[int] { return goset.NewSet(v...) }},
+	}
+
+	for _, f := range factories {
+		t.Run(f.name, func(t *testing.T) {
+			set := f.newSet(1, 2, 3)
+			var buf bytes.Buffer
+			require.NoError(t, gob.NewEncoder(&buf).Encode(set))
+
+			decoded := f.newSet(4)
+			require.NoError(t, gob.NewDecoder(&buf).Decode(decoded))
+			assert.True(t, set.Equal(decoded))
+
+			buf.Reset()
+			require.NoError(t, gob.NewEncoder(&buf).Encode(f.newSet()))
+			require.NoError(t, gob.NewDecoder(&buf).Decode(decoded))
+			assert.Zero(t, decoded.Len())
+
+			gobDecoder := decoded.(gob.GobDecoder)
+			assert.Error(t, gobDecoder.GobDecode(nil))
+			assert.ErrorContains(t, gobDecoder.GobDecode([]byte{99}), "unsupported set format version 99")
+			assert.Error(t, gobDecoder.GobDecode([]byte{1, 2, 3}))
+		})
+	}
+}
+
+func TestBinary(t *testing.T) {
+	t.Run("Int", func(t *testing.T) {
+		for _, set := range []goset.Set[int]{goset.NewSet(-300, 0, 1, 1<<40), goset.NewThreadUnsafeSet(7), goset.NewSet[int]()} {
+			data, err := set.(encoding.BinaryMarshaler).MarshalBinary()
+			require.NoError(t, err)
+
+			decoded := goset.NewSet(99)
+			require.NoError(t, decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary(data))
+			assert.True(t, set.Equal(decoded))
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		set := goset.NewThreadUnsafeSet("", "a", "héllo", string(make([]byte, 300)))
+		data, err := set.(encoding.BinaryMarshaler).MarshalBinary()
+		require.NoError(t, err)
+
+		decoded := goset.NewThreadUnsafeSet[string]()
+		require.NoError(t, decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary(data))
+		assert.True(t, set.Equal(decoded))
+	})
+
+	t.Run("UnsupportedType", func(t *testing.T) {
+		_, err := goset.NewSet(1.5).(encoding.BinaryMarshaler).MarshalBinary()
+		assert.Error(t, err)
+	})
+
+	t.Run("Corrupt", func(t *testing.T) {
+		data, err := goset.NewSet("abc", "de").(encoding.BinaryMarshaler).MarshalBinary()
+		require.NoError(t, err)
+
+		corrupt := [][]byte{
+			nil,
+			{99},
+			{1},
+			{1, 0xff},
+			{1, 200},
+			data[:len(data)-1],
+			append(append([]byte{}, data...), 0),
+		}
+		for _, input := range corrupt {
+			decoded := goset.NewSet("kept")
+			assert.Error(t, decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary(input), "%v", input)
+			assert.True(t, decoded.Equal(goset.NewSet("kept")))
+		}
+
+		data, err = goset.NewSet(1000).(encoding.BinaryMarshaler).MarshalBinary()
+		require.NoError(t, err)
+		assert.Error(t, goset.NewSet[int8]().(encoding.BinaryUnmarshaler).UnmarshalBinary(data))
+	})
+}
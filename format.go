@@ -0,0 +1,33 @@
+package goset
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GoString returns Go source that rebuilds the set by calling the given constructor,
+// e.g. goset.NewSet(1, 2, 3) for the constructor "goset.NewSet", which is handy for turning
+// a runtime set into a test fixture. Elements are formatted with %#v, so struct elements come out
+// as composite literals qualified with their package name, and pointers to structs as &pkg.Type{...}.
+// Formatted elements are sorted as strings so the output is deterministic.
+func GoString[T any](s Set[T], constructor string) string {
+	var items []string
+	s.Each(func(elem T) bool {
+		items = append(items, fmt.Sprintf("%#v", elem))
+		return true
+	})
+	sort.Strings(items)
+	return fmt.Sprintf("%s(%s)", constructor, strings.Join(items, ", "))
+}
+
+// SortedString returns the same representation of s as its String method, but with the elements
+// ordered by less, so the output is byte-identical for equal sets and suitable for logs and golden files.
+func SortedString[T any](s Set[T], less func(a, b T) bool) string {
+	elems := s.ToSortedSlice(less)
+	items := make([]string, len(elems))
+	for i, elem := range elems {
+		items[i] = fmt.Sprintf("%#v", elem)
+	}
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
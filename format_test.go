@@ -0,0 +1,32 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestGoString(t *testing.T) {
+	assert.Equal(t, "goset.NewSet(1, 2, 3)", goset.GoString(goset.NewSet(3, 1, 2), "goset.NewSet"))
+	assert.Equal(t, `goset.NewSet("a", "b")`, goset.GoString(goset.NewSet("b", "a"), "goset.NewSet"))
+	assert.Equal(t, "goset.NewSet[int]()", goset.GoString(goset.NewSet[int](), "goset.NewSet[int]"))
+	assert.Regexp(t, `^goset\.NewSet\(goset_test\.TestType\{ID:1, Name:"One", Importance:1\}\)$`,
+		goset.GoString(goset.NewSet(*testItems[0]), "goset.NewSet"))
+}
+
+func TestSortedString(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	setA := goset.NewSet[int]()
+	setB := goset.NewThreadUnsafeSet[int]()
+	for i := 0; i < 50; i++ {
+		setA.Add(i)
+		setB.Add(49 - i)
+	}
+	assert.Equal(t, goset.SortedString(setA, less), goset.SortedString(setB, less))
+
+	assert.Equal(t, "Set{3, 2, 1}", goset.SortedString(goset.NewSet(1, 3, 2), func(a, b int) bool { return a > b }))
+	assert.Equal(t, "Set{}", goset.SortedString(goset.NewSet[int](), less))
+}
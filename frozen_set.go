@@ -0,0 +1,77 @@
+package goset
+
+type frozenSet[T any] struct {
+	Set[T]
+}
+
+// Assert concrete type:frozenSet adheres to Set interface.
+var _ Set[int] = (*frozenSet[int])(nil)
+
+// frozenMessage is the value frozen sets panic with when they are mutated.
+const frozenMessage = "goset: cannot mutate a frozen set"
+
+// Freeze returns a read-only view of s that panics on any attempt to mutate it, so that a set can be handed out
+// without callers changing shared state. Reads go straight to s, so later changes made through s itself are visible.
+// Clone returns a mutable copy, and the results of Diff, Union and the other operations returning a new set are mutable.
+func Freeze[T any](s Set[T]) Set[T] {
+	if frozen, ok := s.(*frozenSet[T]); ok {
+		return frozen
+	}
+	return &frozenSet[T]{Set: s}
+}
+
+func (s *frozenSet[T]) Add(...T) bool {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) AddSet(Set[T]) bool {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) UnionWith(Set[T]) bool {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) Clear() {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) EachMutable(func(T) bool) {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) Consume() <-chan T {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) IntersectWith(Set[T]) {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) Pop() (T, bool) {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) PopN(int) []T {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) Remove(...T) {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) RemoveSet(Set[T]) {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) RemoveIf(func(T) bool) int {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) ReplaceAll([]T) {
+	panic(frozenMessage)
+}
+
+func (s *frozenSet[T]) Toggle(...T) int {
+	panic(frozenMessage)
+}
@@ -0,0 +1,57 @@
+package goset_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestFreeze(t *testing.T) {
+	const frozenMessage = "goset: cannot mutate a frozen set"
+	inner := goset.NewSet(1, 2, 3)
+	frozen := goset.Freeze(inner)
+
+	mutations := map[string]func(){
+		"Add":           func() { frozen.Add(4) },
+		"AddSet":        func() { frozen.AddSet(goset.NewSet(4)) },
+		"UnionWith":     func() { frozen.UnionWith(goset.NewSet(4)) },
+		"Clear":         func() { frozen.Clear() },
+		"EachMutable":   func() { frozen.EachMutable(func(int) bool { return true }) },
+		"Consume":       func() { frozen.Consume() },
+		"IntersectWith": func() { frozen.IntersectWith(goset.NewSet(1)) },
+		"Pop":           func() { frozen.Pop() },
+		"PopN":          func() { frozen.PopN(1) },
+		"Remove":        func() { frozen.Remove(1) },
+		"RemoveSet":     func() { frozen.RemoveSet(goset.NewSet(1)) },
+		"RemoveIf":      func() { frozen.RemoveIf(func(int) bool { return true }) },
+		"ReplaceAll":    func() { frozen.ReplaceAll([]int{4}) },
+		"Toggle":        func() { frozen.Toggle(1) },
+	}
+	for name, mutate := range mutations {
+		assert.PanicsWithValue(t, frozenMessage, mutate, name)
+	}
+	assert.True(t, inner.Equal(goset.NewSet(1, 2, 3)))
+
+	assert.Equal(t, 3, frozen.Len())
+	assert.True(t, frozen.Contains(1, 2))
+	assert.True(t, frozen.Equal(inner))
+	assert.True(t, goset.NewSet(1).Equal(frozen.Diff(goset.NewSet(2, 3))))
+	var items []int
+	for v := range frozen.Iter() {
+		items = append(items, v)
+	}
+	sort.Ints(items)
+	assert.EqualValues(t, []int{1, 2, 3}, items)
+
+	inner.Add(4)
+	assert.True(t, frozen.Contains(4))
+
+	clone := frozen.Clone()
+	assert.True(t, clone.Add(5))
+	assert.False(t, frozen.Contains(5))
+
+	assert.Same(t, frozen, goset.Freeze(frozen))
+}
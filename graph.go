@@ -0,0 +1,74 @@
+package goset
+
+// TransitiveClosure returns a new set of every pair (a, c) such that c can be reached from a by following
+// one or more edges in the given set. It runs a breadth-first search from every node, costing O(V·E).
+// A node is paired with itself only if it lies on a cycle.
+func TransitiveClosure(edges Set[Pair[int, int]]) Set[Pair[int, int]] {
+	adjacent := make(map[int][]int)
+	edges.Each(func(edge Pair[int, int]) bool {
+		adjacent[edge.First] = append(adjacent[edge.First], edge.Second)
+		return true
+	})
+
+	closure := NewSet[Pair[int, int]]()
+	for source := range adjacent {
+		visited := make(map[int]bool)
+		queue := append([]int(nil), adjacent[source]...)
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+			if visited[node] {
+				continue
+			}
+			visited[node] = true
+			closure.Add(Pair[int, int]{First: source, Second: node})
+			queue = append(queue, adjacent[node]...)
+		}
+	}
+	return closure
+}
+
+// EquivalenceClasses partitions s into fresh sets of elements that are transitively related, so that a and c share
+// a class whenever related(a, b) and related(b, c). related should be symmetric. It is evaluated once for every
+// pair of elements, costing O(n²) calls, and the classes are merged with a union-find.
+func EquivalenceClasses[T comparable](s Set[T], related func(a, b T) bool) []Set[T] {
+	elems := s.ToSlice()
+	parent := make([]int, len(elems))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range elems {
+		for j := i + 1; j < len(elems); j++ {
+			if find(i) != find(j) && related(elems[i], elems[j]) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	classes := make(map[int]Set[T])
+	var order []int
+	for i, elem := range elems {
+		root := find(i)
+		class, ok := classes[root]
+		if !ok {
+			class = NewSet[T]()
+			classes[root] = class
+			order = append(order, root)
+		}
+		class.Add(elem)
+	}
+
+	result := make([]Set[T], 0, len(order))
+	for _, root := range order {
+		result = append(result, classes[root])
+	}
+	return result
+}
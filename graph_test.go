@@ -0,0 +1,52 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func edge(from, to int) goset.Pair[int, int] {
+	return goset.Pair[int, int]{First: from, Second: to}
+}
+
+func TestTransitiveClosure(t *testing.T) {
+	chain := goset.NewSet(edge(1, 2), edge(2, 3), edge(3, 4), edge(5, 6))
+	expected := goset.NewSet(
+		edge(1, 2), edge(1, 3), edge(1, 4),
+		edge(2, 3), edge(2, 4),
+		edge(3, 4),
+		edge(5, 6),
+	)
+	assert.True(t, expected.Equal(goset.TransitiveClosure(chain)))
+
+	cycle := goset.NewSet(edge(1, 2), edge(2, 1))
+	expected = goset.NewSet(edge(1, 2), edge(2, 1), edge(1, 1), edge(2, 2))
+	assert.True(t, expected.Equal(goset.TransitiveClosure(cycle)))
+
+	assert.Zero(t, goset.TransitiveClosure(goset.NewSet[goset.Pair[int, int]]()).Len())
+}
+
+func TestEquivalenceClasses(t *testing.T) {
+	// Numbers within 2 of each other are related, which chains 1..5 together and 20..23 together.
+	set := goset.NewSet(1, 3, 5, 20, 21, 23)
+	near := func(a, b int) bool { return a-b <= 2 && b-a <= 2 }
+
+	classes := goset.EquivalenceClasses(set, near)
+	assert.Len(t, classes, 2)
+
+	var low, high goset.Set[int]
+	for _, class := range classes {
+		if class.Contains(1) {
+			low = class
+		} else {
+			high = class
+		}
+	}
+	assert.True(t, goset.NewSet(1, 3, 5).Equal(low))
+	assert.True(t, goset.NewSet(20, 21, 23).Equal(high))
+
+	assert.Empty(t, goset.EquivalenceClasses(goset.NewSet[int](), near))
+}
@@ -0,0 +1,88 @@
+package goset
+
+import (
+	"math/bits"
+)
+
+// IntComplement returns a new set of every integer in [0, n) that is not in s.
+// Elements of s outside [0, n) are ignored. When s was created by NewBitSet or NewThreadUnsafeBitSet, the complement
+// is computed a word at a time and is a bit set of the same kind.
+func IntComplement(s Set[int], n int) Set[int] {
+	if b, unlock := asBitSet(s); b != nil {
+		unlock()
+		words := bitWords(s, n)
+		for i := range words {
+			words[i] = ^words[i]
+		}
+		if rest := n % 64; rest != 0 && len(words) > 0 {
+			words[len(words)-1] &= 1<<rest - 1
+		}
+		complement := newUnsafeBitSetFromWords(words)
+		if s == Set[int](b) {
+			return complement
+		}
+		return &safeSet[int]{set: complement}
+	}
+
+	complement := NewSet[int]()
+	for i := 0; i < n; i++ {
+		if !s.Contains(i) {
+			complement.Add(i)
+		}
+	}
+	return complement
+}
+
+// BitsetXORCount returns the number of integers in [0, universe) that are in exactly one of a and b,
+// which is the Hamming distance between the two sets projected onto a bitset of that universe.
+// Both sets are projected onto 64-bit words that are compared word by word; the words of sets created by NewBitSet
+// are used as they are. Elements outside the universe are ignored.
+func BitsetXORCount(a, b Set[int], universe int) int {
+	wordsA := bitWords(a, universe)
+	wordsB := bitWords(b, universe)
+	count := 0
+	for i := range wordsA {
+		count += bits.OnesCount64(wordsA[i] ^ wordsB[i])
+	}
+	return count
+}
+
+// bitWords projects the elements of s in [0, universe) onto a bitset of 64-bit words.
+func bitWords(s Set[int], universe int) []uint64 {
+	words := make([]uint64, (max(universe, 0)+63)/64)
+	if b, unlock := asBitSet(s); b != nil {
+		defer unlock()
+		copy(words, b.words)
+		if rest := universe % 64; rest != 0 && len(words) > 0 {
+			words[len(words)-1] &= 1<<rest - 1
+		}
+		for elem := range b.sparse {
+			if elem >= 0 && elem < universe {
+				words[elem/64] |= 1 << (elem % 64)
+			}
+		}
+		return words
+	}
+	s.Each(func(elem int) bool {
+		if elem >= 0 && elem < universe {
+			words[elem/64] |= 1 << (elem % 64)
+		}
+		return true
+	})
+	return words
+}
+
+// asBitSet returns the bit set backing s, if any, along with a function releasing the read lock taken
+// on a thread-safe bit set. It returns a nil bit set when s is not backed by one.
+func asBitSet(s Set[int]) (*unsafeBitSet, func()) {
+	switch set := s.(type) {
+	case *unsafeBitSet:
+		return set, func() {}
+	case *safeSet[int]:
+		if b, ok := set.set.(*unsafeBitSet); ok {
+			set.RLock()
+			return b, set.RUnlock
+		}
+	}
+	return nil, nil
+}
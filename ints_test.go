@@ -0,0 +1,71 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestIntComplement(t *testing.T) {
+	set := goset.NewSet(-5, 0, 3, 64, 99, 150)
+	complement := goset.IntComplement(set, 100)
+
+	assert.Equal(t, 96, complement.Len())
+	assert.False(t, complement.Contains(0))
+	assert.False(t, complement.Contains(150))
+	assert.True(t, complement.Contains(1, 2, 4, 63, 65, 98))
+
+	for i := 0; i < 100; i++ {
+		assert.NotEqual(t, set.Contains(i), complement.Contains(i), "%d must be in exactly one set", i)
+	}
+
+	assert.Zero(t, goset.IntComplement(set, 0).Len())
+	assert.Equal(t, 10, goset.IntComplement(goset.NewSet[int](), 10).Len())
+}
+
+func TestIntComplementOfBitSet(t *testing.T) {
+	sets := map[string]goset.Set[int]{
+		"BitSet":             goset.NewBitSet(0, -5, 0, 3, 64, 99, 150, 1<<40),
+		"ThreadUnsafeBitSet": goset.NewThreadUnsafeBitSet(0, -5, 0, 3, 64, 99, 150, 1<<40),
+	}
+	for name, set := range sets {
+		t.Run(name, func(t *testing.T) {
+			for _, n := range []int{0, 1, 63, 64, 100, 129} {
+				complement := goset.IntComplement(set, n)
+				assert.True(t, complement.Equal(goset.IntComplement(goset.NewSet(set.ToSlice()...), n)), "n %d", n)
+				assert.False(t, complement.Stats().MapBacked)
+			}
+			complement := goset.IntComplement(set, 100)
+			assert.Equal(t, 96, complement.Len())
+			assert.True(t, complement.Contains(1, 2, 4, 63, 65, 98))
+			assert.False(t, complement.ContainsAny(-5, 0, 3, 64, 99, 100, 150))
+		})
+	}
+}
+
+func TestBitsetXORCount(t *testing.T) {
+	a := goset.NewSet(-1, 0, 1, 63, 64, 65, 127, 200)
+	b := goset.NewThreadUnsafeSet(1, 2, 64, 128, 199, 300)
+
+	for _, universe := range []int{0, 1, 64, 65, 128, 129, 200, 201, 1000} {
+		naive := 0
+		a.SymmetricDiff(b).Each(func(v int) bool {
+			if v >= 0 && v < universe {
+				naive++
+			}
+			return true
+		})
+		assert.Equal(t, naive, goset.BitsetXORCount(a, b, universe), "universe %d", universe)
+	}
+
+	assert.Zero(t, goset.BitsetXORCount(a, a.Clone(), 1000))
+
+	bitA := goset.NewBitSet(0, 0, 1, 63, 64, 65, 127, 200)
+	bitB := goset.NewThreadUnsafeBitSet(0, 1, 2, 64, 128, 199, 300)
+	for _, universe := range []int{0, 1, 64, 65, 128, 129, 200, 201, 1000} {
+		assert.Equal(t, goset.BitsetXORCount(a, b, universe), goset.BitsetXORCount(bitA, bitB, universe), "universe %d", universe)
+		assert.Equal(t, goset.BitsetXORCount(a, b, universe), goset.BitsetXORCount(a, bitB, universe), "universe %d", universe)
+	}
+}
@@ -0,0 +1,26 @@
+package goset
+
+import (
+	"fmt"
+)
+
+// CheckInvariants verifies that s is internally consistent: ToSlice returns Len distinct elements and
+// Contains reports every one of them. It is meant as a testing aid for catching corruption caused by
+// locking bugs, and returns an error describing the first inconsistency found.
+func CheckInvariants[T comparable](s Set[T]) error {
+	elems := s.ToSlice()
+	if n := s.Len(); n != len(elems) {
+		return fmt.Errorf("set length %d does not match its %d listed elements", n, len(elems))
+	}
+	seen := make(map[T]struct{}, len(elems))
+	for _, elem := range elems {
+		if _, ok := seen[elem]; ok {
+			return fmt.Errorf("element %v is listed more than once", elem)
+		}
+		seen[elem] = struct{}{}
+		if !s.Contains(elem) {
+			return fmt.Errorf("listed element %v is not contained in the set", elem)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package goset_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+type miscountedSet struct {
+	goset.Set[int]
+}
+
+func (s miscountedSet) Len() int {
+	return s.Set.Len() + 1
+}
+
+func TestCheckInvariants(t *testing.T) {
+	assert.NoError(t, goset.CheckInvariants(goset.NewSet(1, 2, 3)))
+	assert.NoError(t, goset.CheckInvariants(goset.NewSet[int]()))
+	assert.Error(t, goset.CheckInvariants[int](miscountedSet{goset.NewSet(1, 2, 3)}))
+}
+
+func TestCheckInvariantsAfterConcurrentMutation(t *testing.T) {
+	set := goset.NewSet[int]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				v := (offset*31 + j) % 200
+				switch j % 4 {
+				case 0:
+					set.Add(v)
+				case 1:
+					set.Toggle(v)
+				case 2:
+					set.Remove(v + 1)
+				default:
+					set.AddSet(goset.NewThreadUnsafeSet(v, v+2))
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.NoError(t, goset.CheckInvariants(set))
+}
@@ -0,0 +1,54 @@
+package goset
+
+// EachKeyed iterates over the items in a resolving set along with the key each item is stored under.
+// Breaks iteration if the given function returns false. Resolving sets other than those of this package
+// have their keys computed with KeyFunc.
+func EachKeyed[T any, U comparable](s ResolvingSet[T, U], fn func(key U, v T) bool) {
+	switch set := s.(type) {
+	case *safeResolvingSet[T, U]:
+		set.RLock()
+		defer set.RUnlock()
+		EachKeyed(set.resolving, fn)
+	case *unsafeResolvingSet[T, U]:
+		for key, elem := range set.set {
+			if !fn(key, elem) {
+				break
+			}
+		}
+	default:
+		keyGetter := s.KeyFunc()
+		s.Each(func(elem T) bool {
+			return fn(keyGetter(elem), elem)
+		})
+	}
+}
+
+// MaxCollision returns the key shared by the most items in the given slice and the number of items sharing it.
+// A count of 1 means no two items collide, which makes it a quick health check of a KeyGetter before building
+// a resolving set. Ties are broken by the key that reaches the count first. An empty slice returns a count of 0.
+func MaxCollision[T any, U comparable](items []T, keyGetter KeyGetter[T, U]) (key U, count int) {
+	counts := make(map[U]int, len(items))
+	for _, item := range items {
+		k := keyGetter(item)
+		counts[k]++
+		if counts[k] > count {
+			key = k
+			count = counts[k]
+		}
+	}
+	return key, count
+}
+
+// BuildResolving returns a thread-safe resolving set of the given items along with the number of items
+// whose key was already in the set when they were added, that is, how many collisions the resolver settled.
+func BuildResolving[T any, U comparable](items []T, keyGetter KeyGetter[T, U], resolver Resolver[T]) (ResolvingSet[T, U], int) {
+	set := newUnsafeResolvingSet(keyGetter, resolver)
+	collisions := 0
+	for _, item := range items {
+		if set.contains(item) {
+			collisions++
+		}
+		set.Add(item)
+	}
+	return wrapResolving(set), collisions
+}
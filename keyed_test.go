@@ -0,0 +1,78 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestMaxCollision(t *testing.T) {
+	byID := func(item *TestType) int { return item.ID }
+
+	key, count := goset.MaxCollision(testItems, byID)
+	assert.Equal(t, 1, key)
+	assert.Equal(t, 3, count)
+
+	byLength := func(s string) int { return len(s) }
+	key, count = goset.MaxCollision([]string{"a", "bb", "cc", "dd", "eee", "ff"}, byLength)
+	assert.Equal(t, 2, key)
+	assert.Equal(t, 4, count)
+
+	_, count = goset.MaxCollision([]string{"a", "bb", "ccc"}, byLength)
+	assert.Equal(t, 1, count)
+
+	_, count = goset.MaxCollision(nil, byLength)
+	assert.Zero(t, count)
+}
+
+func TestBuildResolving(t *testing.T) {
+	set, collisions := goset.BuildResolving(testItems,
+		func(item *TestType) int {
+			return item.ID
+		},
+		func(foundItem, newItem *TestType) (*TestType, bool) {
+			if newItem.Importance > foundItem.Importance {
+				return newItem, true
+			}
+			return foundItem, false
+		})
+
+	assert.Equal(t, 3, collisions)
+	actualItems := set.ToSlice()
+	sortTestItems(actualItems)
+	assert.EqualValues(t, []*TestType{testItems[5], testItems[3], testItems[2]}, actualItems)
+
+	set, collisions = goset.BuildResolving(nil, func(item *TestType) int { return item.ID }, nil)
+	assert.Zero(t, collisions)
+	assert.Zero(t, set.Len())
+}
+
+// wrappedResolvingSet is a ResolvingSet implemented outside the package.
+type wrappedResolvingSet struct {
+	goset.ResolvingSet[*TestType, int]
+}
+
+func TestEachKeyedOnOtherResolvingSets(t *testing.T) {
+	set := wrappedResolvingSet{goset.NewResolvingSet(func(item *TestType) int { return item.ID }, nil)}
+	set.Add(testItems...)
+
+	keys := map[int]*TestType{}
+	goset.EachKeyed[*TestType, int](set, func(key int, item *TestType) bool {
+		keys[key] = item
+		return true
+	})
+	assert.Len(t, keys, 3)
+	for key, item := range keys {
+		assert.Equal(t, key, item.ID)
+		assert.True(t, set.Contains(item))
+	}
+
+	visited := 0
+	goset.EachKeyed[*TestType, int](set, func(int, *TestType) bool {
+		visited++
+		return false
+	})
+	assert.Equal(t, 1, visited)
+}
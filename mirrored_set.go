@@ -0,0 +1,212 @@
+package goset
+
+type mirroredSet[T any] struct {
+	Set[T]
+	onAdd    func(T)
+	onRemove func(T)
+}
+
+// Assert concrete type:mirroredSet adheres to Set interface.
+var _ Set[int] = (*mirroredSet[int])(nil)
+
+// resolvingPrimary is implemented by the resolving sets of this package, whose Add may replace a stored element
+// and whose Remove removes the stored element with the key of a value rather than the value itself.
+type resolvingPrimary[T any] interface {
+	upsert(v T) (stored T, previous T, existed bool, replaced bool)
+	RemoveReturning(v ...T) []T
+}
+
+// NewMirroredSet returns a set that delegates to primary and reports every element it adds or removes
+// to onAdd and onRemove, so that an external index can be kept in sync. Either sink may be nil.
+// Clear reports each removed element to onRemove. When primary is a resolving set, the sinks receive the stored
+// elements: an element replaced by the resolver is reported to onRemove and its replacement to onAdd, and Remove
+// reports the element stored under the key of each value. The sinks are called after the primary set is changed
+// and are not atomic with the change itself.
+func NewMirroredSet[T any](primary Set[T], onAdd func(T), onRemove func(T)) Set[T] {
+	return &mirroredSet[T]{
+		Set:      primary,
+		onAdd:    onAdd,
+		onRemove: onRemove,
+	}
+}
+
+func (s *mirroredSet[T]) added(v T) {
+	if s.onAdd != nil {
+		s.onAdd(v)
+	}
+}
+
+func (s *mirroredSet[T]) removed(v T) {
+	if s.onRemove != nil {
+		s.onRemove(v)
+	}
+}
+
+func (s *mirroredSet[T]) Add(v ...T) bool {
+	var ret bool
+	if primary, ok := s.Set.(resolvingPrimary[T]); ok {
+		for _, val := range v {
+			stored, previous, existed, replaced := primary.upsert(val)
+			if replaced {
+				s.removed(previous)
+			}
+			if !existed || replaced {
+				s.added(stored)
+				ret = true
+			}
+		}
+		return ret
+	}
+	for _, val := range v {
+		if s.Set.Add(val) {
+			s.added(val)
+			ret = true
+		}
+	}
+	return ret
+}
+
+func (s *mirroredSet[T]) AddSet(other Set[T]) bool {
+	return s.Add(other.ToSlice()...)
+}
+
+func (s *mirroredSet[T]) UnionWith(other Set[T]) bool {
+	return s.AddSet(other)
+}
+
+func (s *mirroredSet[T]) Remove(v ...T) {
+	if primary, ok := s.Set.(resolvingPrimary[T]); ok {
+		for _, elem := range primary.RemoveReturning(v...) {
+			s.removed(elem)
+		}
+		return
+	}
+	for _, val := range v {
+		if s.Set.Contains(val) {
+			s.Set.Remove(val)
+			s.removed(val)
+		}
+	}
+}
+
+func (s *mirroredSet[T]) RemoveSet(other Set[T]) {
+	s.Remove(other.ToSlice()...)
+}
+
+func (s *mirroredSet[T]) IntersectWith(other Set[T]) {
+	if other == Set[T](s) {
+		return
+	}
+	s.EachMutable(func(elem T) bool {
+		return other.Contains(elem)
+	})
+}
+
+func (s *mirroredSet[T]) Pop() (T, bool) {
+	elem, ok := s.Set.Pop()
+	if ok {
+		s.removed(elem)
+	}
+	return elem, ok
+}
+
+func (s *mirroredSet[T]) PopN(n int) []T {
+	popped := s.Set.PopN(n)
+	for _, elem := range popped {
+		s.removed(elem)
+	}
+	return popped
+}
+
+func (s *mirroredSet[T]) Clear() {
+	elems := s.Set.ToSlice()
+	s.Set.Clear()
+	for _, elem := range elems {
+		s.removed(elem)
+	}
+}
+
+func (s *mirroredSet[T]) Consume() <-chan T {
+	elems := s.Set.Consume()
+	consumed := make(chan T, len(elems))
+	for elem := range elems {
+		s.removed(elem)
+		consumed <- elem
+	}
+	close(consumed)
+	return consumed
+}
+
+func (s *mirroredSet[T]) EachMutable(fn func(T) (keep bool)) {
+	var removed []T
+	s.Set.EachMutable(func(elem T) bool {
+		keep := fn(elem)
+		if !keep {
+			removed = append(removed, elem)
+		}
+		return keep
+	})
+	for _, elem := range removed {
+		s.removed(elem)
+	}
+}
+
+func (s *mirroredSet[T]) RemoveIf(pred func(T) bool) int {
+	var removed []T
+	s.Set.EachMutable(func(elem T) bool {
+		if pred(elem) {
+			removed = append(removed, elem)
+			return false
+		}
+		return true
+	})
+	for _, elem := range removed {
+		s.removed(elem)
+	}
+	return len(removed)
+}
+
+func (s *mirroredSet[T]) Toggle(v ...T) int {
+	change := 0
+	if primary, ok := s.Set.(resolvingPrimary[T]); ok {
+		for _, val := range v {
+			if removed := primary.RemoveReturning(val); len(removed) > 0 {
+				s.removed(removed[0])
+				change--
+			} else if stored, _, existed, _ := primary.upsert(val); !existed {
+				s.added(stored)
+				change++
+			}
+		}
+		return change
+	}
+	for _, val := range v {
+		if s.Set.Contains(val) {
+			s.Set.Remove(val)
+			s.removed(val)
+			change--
+		} else {
+			s.Set.Add(val)
+			s.added(val)
+			change++
+		}
+	}
+	return change
+}
+
+func (s *mirroredSet[T]) ReplaceAll(items []T) {
+	before := s.Set.Clone()
+	s.Set.ReplaceAll(items)
+	before.Each(func(elem T) bool {
+		if !s.Set.Contains(elem) {
+			s.removed(elem)
+		}
+		return true
+	})
+	s.Set.Each(func(elem T) bool {
+		if !before.Contains(elem) {
+			s.added(elem)
+		}
+		return true
+	})
+}
@@ -0,0 +1,118 @@
+package goset_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestMirroredSet(t *testing.T) {
+	var added, removed []int
+	set := goset.NewMirroredSet(goset.NewSet(1, 2),
+		func(v int) { added = append(added, v) },
+		func(v int) { removed = append(removed, v) })
+
+	assert.True(t, set.Add(2, 3, 4))
+	assert.EqualValues(t, []int{3, 4}, added)
+	assert.False(t, set.Add(1))
+	assert.EqualValues(t, []int{3, 4}, added)
+
+	set.Remove(1, 5)
+	assert.EqualValues(t, []int{1}, removed)
+
+	v, ok := set.Pop()
+	assert.True(t, ok)
+	assert.EqualValues(t, []int{1, v}, removed)
+
+	set.EachMutable(func(int) bool { return false })
+	assert.Zero(t, set.Len())
+	actualItems := removed[1:]
+	sort.Ints(actualItems)
+	assert.EqualValues(t, []int{2, 3, 4}, actualItems)
+
+	removed = nil
+	set.Add(7, 8)
+	set.Clear()
+	sort.Ints(removed)
+	assert.EqualValues(t, []int{7, 8}, removed)
+	assert.EqualValues(t, []int{3, 4, 7, 8}, added)
+
+	_, ok = set.Pop()
+	assert.False(t, ok)
+	assert.Len(t, removed, 2)
+
+	added, removed = nil, nil
+	set.Add(1)
+	assert.Equal(t, 0, set.Toggle(1, 2))
+	assert.EqualValues(t, []int{1, 2}, added)
+	assert.EqualValues(t, []int{1}, removed)
+
+	removed = nil
+	var consumed []int
+	for v := range set.Consume() {
+		consumed = append(consumed, v)
+	}
+	assert.EqualValues(t, []int{2}, consumed)
+	assert.EqualValues(t, []int{2}, removed)
+
+	added, removed = nil, nil
+	set.Add(1, 2)
+	set.ReplaceAll([]int{2, 3})
+	assert.EqualValues(t, []int{1, 2, 3}, added)
+	assert.EqualValues(t, []int{1}, removed)
+
+	added = nil
+	assert.True(t, set.AddSet(goset.NewThreadUnsafeSet(3, 4)))
+	assert.EqualValues(t, []int{4}, added)
+
+	removed = nil
+	popped := set.PopN(2)
+	assert.Len(t, popped, 2)
+	assert.EqualValues(t, popped, removed)
+
+	removed = nil
+	set.Add(5, 6, 7)
+	assert.Equal(t, 1, set.RemoveIf(func(v int) bool { return v == 6 }))
+	assert.EqualValues(t, []int{6}, removed)
+}
+
+func TestMirroredResolvingSet(t *testing.T) {
+	var added, removed []*TestType
+	byID := func(item *TestType) int { return item.ID }
+	byImportance := func(a, b *TestType) bool { return a.Importance < b.Importance }
+	primaries := map[string]goset.ResolvingSet[*TestType, int]{
+		"UnsafeResolvingSet": goset.NewThreadUnsafeResolvingSet(byID, goset.MaxResolver(byImportance)),
+		"SafeResolvingSet":   goset.NewResolvingSet(byID, goset.MaxResolver(byImportance)),
+	}
+	for name, primary := range primaries {
+		t.Run(name, func(t *testing.T) {
+			added, removed = nil, nil
+			set := goset.NewMirroredSet[*TestType](primary,
+				func(item *TestType) { added = append(added, item) },
+				func(item *TestType) { removed = append(removed, item) })
+
+			low := &TestType{ID: 1, Name: "Low", Importance: 1}
+			high := &TestType{ID: 1, Name: "High", Importance: 2}
+			assert.True(t, set.Add(low))
+			assert.True(t, set.Add(high))
+			assert.False(t, set.Add(low))
+			assert.Equal(t, []*TestType{low, high}, added)
+			assert.Equal(t, []*TestType{low}, removed)
+
+			// removing by an equal key reports the stored element
+			removed = nil
+			set.Remove(&TestType{ID: 1}, &TestType{ID: 2})
+			assert.Equal(t, []*TestType{high}, removed)
+			assert.Zero(t, set.Len())
+
+			added, removed = nil, nil
+			assert.Equal(t, 1, set.Toggle(low))
+			assert.Equal(t, -1, set.Toggle(&TestType{ID: 1}))
+			assert.Equal(t, []*TestType{low}, added)
+			assert.Equal(t, []*TestType{low}, removed)
+		})
+	}
+}
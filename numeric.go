@@ -0,0 +1,38 @@
+package goset
+
+import "math"
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Histogram returns the number of elements of s falling into each of the given number of equal-width buckets
+// spanning [lo, hi). Elements below lo are clamped into the first bucket and elements at or above hi into the last.
+// NaN elements fall into no bucket and are not counted. It returns nil if buckets is not positive.
+func Histogram[T Number](s Set[T], buckets int, lo, hi T) []int {
+	if buckets <= 0 {
+		return nil
+	}
+
+	counts := make([]int, buckets)
+	width := (float64(hi) - float64(lo)) / float64(buckets)
+	s.Each(func(elem T) bool {
+		var bucket int
+		switch {
+		case math.IsNaN(float64(elem)):
+			return true
+		case elem < lo:
+			bucket = 0
+		case elem >= hi:
+			bucket = buckets - 1
+		default:
+			bucket = min(int((float64(elem)-float64(lo))/width), buckets-1)
+		}
+		counts[bucket]++
+		return true
+	})
+	return counts
+}
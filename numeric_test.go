@@ -0,0 +1,24 @@
+package goset_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestHistogram(t *testing.T) {
+	set := goset.NewSet(-5, 0, 1, 9, 10, 15, 19, 20, 25, 39, 40, 100)
+	assert.Equal(t, []int{4, 3, 2, 3}, goset.Histogram(set, 4, 0, 40))
+
+	floats := goset.NewSet(0.1, 0.2, 0.5, 0.75, 0.99)
+	assert.Equal(t, []int{2, 3}, goset.Histogram(floats, 2, 0, 1.0))
+
+	floats.Add(math.NaN(), math.Inf(-1), math.Inf(1))
+	assert.Equal(t, []int{3, 4}, goset.Histogram(floats, 2, 0, 1.0))
+
+	assert.Equal(t, []int{0, 0, 0}, goset.Histogram(goset.NewSet[int](), 3, 0, 10))
+	assert.Nil(t, goset.Histogram(set, 0, 0, 40))
+}
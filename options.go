@@ -0,0 +1,36 @@
+package goset
+
+// Option configures a set created by NewSetWithOptions.
+type Option func(*options)
+
+type options struct {
+	bloomExpectedN int
+	bloomFPR       float64
+}
+
+// WithBloomPrefilter keeps a Bloom filter alongside the set, sized for expectedN elements
+// at the given false positive rate. Contains consults the filter without taking the set's lock
+// and only falls through to the locked lookup when the filter reports a possible hit, which
+// pays off for large sets queried mostly for absent elements. Removed elements stay in the filter
+// until the set is cleared, so heavy removal raises the false positive rate.
+// The prefilter is not carried over to clones or to the results of set operations.
+func WithBloomPrefilter(expectedN int, fpr float64) Option {
+	return func(o *options) {
+		o.bloomExpectedN = expectedN
+		o.bloomFPR = fpr
+	}
+}
+
+// NewSetWithOptions returns a thread-safe set configured by the given options.
+func NewSetWithOptions[T comparable](opts ...Option) Set[T] {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	set := newSafeSimpleSet[T]()
+	if o.bloomFPR > 0 {
+		set.filter = newBloomFilter[T](o.bloomExpectedN, o.bloomFPR)
+	}
+	return set
+}
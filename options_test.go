@@ -0,0 +1,39 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestBloomPrefilter(t *testing.T) {
+	set := goset.NewSetWithOptions[int](goset.WithBloomPrefilter(1000, 0.01))
+	for i := 0; i < 1000; i++ {
+		set.Add(i * 2)
+	}
+
+	for i := 0; i < 1000; i++ {
+		assert.True(t, set.Contains(i*2))
+		assert.False(t, set.Contains(i*2+1))
+	}
+	assert.True(t, set.Contains(0, 2, 4))
+	assert.False(t, set.Contains(0, 2, 5))
+	assert.True(t, set.ContainsAny(1, 3, 4))
+	assert.False(t, set.ContainsAny(1, 3, 5))
+
+	set.Remove(2)
+	assert.False(t, set.Contains(2))
+
+	set.Toggle(2, 4)
+	assert.True(t, set.Contains(2))
+	assert.False(t, set.Contains(4))
+
+	set.Clear()
+	assert.Zero(t, set.Len())
+	assert.False(t, set.Contains(0))
+
+	set.Add(0)
+	assert.True(t, set.Contains(0))
+}
@@ -0,0 +1,397 @@
+package goset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// orderedNode is an element of the doubly linked list that records insertion order.
+type orderedNode[T comparable] struct {
+	elem       T
+	prev, next *orderedNode[T]
+}
+
+// unsafeOrderedSet remembers the order in which its elements were added. A map from each element to its node in a
+// doubly linked list gives O(1) Add, Contains and Remove, while the list is walked to visit elements in order.
+// Re-adding an element that is already present does not move it.
+type unsafeOrderedSet[T comparable] struct {
+	nodes      map[T]*orderedNode[T]
+	head, tail *orderedNode[T]
+}
+
+// Assert concrete type:unsafeOrderedSet adheres to Set interface.
+var _ Set[string] = (*unsafeOrderedSet[string])(nil)
+
+func newUnsafeOrderedSet[T comparable]() *unsafeOrderedSet[T] {
+	return &unsafeOrderedSet[T]{
+		nodes: make(map[T]*orderedNode[T]),
+	}
+}
+
+func (s *unsafeOrderedSet[T]) add(v T) bool {
+	if _, ok := s.nodes[v]; ok {
+		return false
+	}
+	node := &orderedNode[T]{elem: v, prev: s.tail}
+	if s.tail == nil {
+		s.head = node
+	} else {
+		s.tail.next = node
+	}
+	s.tail = node
+	s.nodes[v] = node
+	return true
+}
+
+// remove unlinks v from the list and deletes it from the map.
+func (s *unsafeOrderedSet[T]) remove(v T) bool {
+	node, ok := s.nodes[v]
+	if !ok {
+		return false
+	}
+	if node.prev == nil {
+		s.head = node.next
+	} else {
+		node.prev.next = node.next
+	}
+	if node.next == nil {
+		s.tail = node.prev
+	} else {
+		node.next.prev = node.prev
+	}
+	delete(s.nodes, v)
+	return true
+}
+
+func (s *unsafeOrderedSet[T]) contains(v T) bool {
+	_, ok := s.nodes[v]
+	return ok
+}
+
+func (s *unsafeOrderedSet[T]) Add(v ...T) bool {
+	var ret bool
+	for _, val := range v {
+		if s.add(val) {
+			ret = true
+		}
+	}
+	return ret
+}
+
+func (s *unsafeOrderedSet[T]) AddSet(other Set[T]) bool {
+	if other == Set[T](s) {
+		return false
+	}
+	prevLen := s.Len()
+	other.Each(func(elem T) bool {
+		s.add(elem)
+		return true
+	})
+	return prevLen != s.Len()
+}
+
+func (s *unsafeOrderedSet[T]) WouldAdd(v T) bool {
+	return !s.contains(v)
+}
+
+func (s *unsafeOrderedSet[T]) Len() int {
+	return len(s.nodes)
+}
+
+func (s *unsafeOrderedSet[T]) IsEmpty() bool {
+	return len(s.nodes) == 0
+}
+
+func (s *unsafeOrderedSet[T]) Stats() SetStats {
+	return SetStats{Len: s.Len(), IsEmpty: s.IsEmpty(), MapBacked: true}
+}
+
+func (s *unsafeOrderedSet[T]) Clear() {
+	s.nodes = make(map[T]*orderedNode[T])
+	s.head, s.tail = nil, nil
+}
+
+func (s *unsafeOrderedSet[T]) Clone() Set[T] {
+	clone := newUnsafeOrderedSet[T]()
+	s.Each(func(elem T) bool {
+		clone.add(elem)
+		return true
+	})
+	return clone
+}
+
+func (s *unsafeOrderedSet[T]) CloneWith(copy func(T) T) Set[T] {
+	clone := newUnsafeOrderedSet[T]()
+	s.Each(func(elem T) bool {
+		clone.add(copy(elem))
+		return true
+	})
+	return clone
+}
+
+func (s *unsafeOrderedSet[T]) Contains(v ...T) bool {
+	for _, val := range v {
+		if !s.contains(val) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *unsafeOrderedSet[T]) ContainsAny(v ...T) bool {
+	for _, val := range v {
+		if s.contains(val) {
+			return true
+		}
+	}
+	return false
+}
+
+// Each visits the elements in insertion order.
+func (s *unsafeOrderedSet[T]) Each(fn func(T) bool) {
+	for node := s.head; node != nil; node = node.next {
+		if !fn(node.elem) {
+			return
+		}
+	}
+}
+
+func (s *unsafeOrderedSet[T]) EachMutable(fn func(T) (keep bool)) {
+	for node := s.head; node != nil; {
+		next := node.next
+		if !fn(node.elem) {
+			s.remove(node.elem)
+		}
+		node = next
+	}
+}
+
+func (s *unsafeOrderedSet[T]) EachSnapshot(fn func(T) bool) {
+	eachOf(s.ToSlice(), fn)
+}
+
+func (s *unsafeOrderedSet[T]) Filter(pred func(T) bool) Set[T] {
+	filtered := newUnsafeOrderedSet[T]()
+	s.Each(func(elem T) bool {
+		if pred(elem) {
+			filtered.add(elem)
+		}
+		return true
+	})
+	return filtered
+}
+
+func (s *unsafeOrderedSet[T]) Partition(pred func(T) bool) (Set[T], Set[T]) {
+	matching, rest := newUnsafeOrderedSet[T](), newUnsafeOrderedSet[T]()
+	s.Each(func(elem T) bool {
+		if pred(elem) {
+			matching.add(elem)
+		} else {
+			rest.add(elem)
+		}
+		return true
+	})
+	return matching, rest
+}
+
+func (s *unsafeOrderedSet[T]) Any(pred func(T) bool) bool {
+	return anyOf(s.Each, pred)
+}
+
+func (s *unsafeOrderedSet[T]) All(pred func(T) bool) bool {
+	return allOf(s.Each, pred)
+}
+
+// Diff keeps the order of this set.
+func (s *unsafeOrderedSet[T]) Diff(other Set[T]) Set[T] {
+	return diffByContains[T](newUnsafeOrderedSet[T](), s, other)
+}
+
+func (s *unsafeOrderedSet[T]) DiffLen(other Set[T]) int {
+	return diffLenByContains[T](s, other)
+}
+
+func (s *unsafeOrderedSet[T]) SymmetricDiff(other Set[T]) Set[T] {
+	return symmetricDiffByContains[T](newUnsafeOrderedSet[T](), s, other)
+}
+
+func (s *unsafeOrderedSet[T]) Equal(other Set[T]) bool {
+	return equalByContains[T](s, other)
+}
+
+func (s *unsafeOrderedSet[T]) Intersect(other Set[T]) Set[T] {
+	return intersectByContains[T](newUnsafeOrderedSet[T](), s, other)
+}
+
+func (s *unsafeOrderedSet[T]) IntersectLen(other Set[T]) int {
+	return intersectLenByContains[T](s, other)
+}
+
+func (s *unsafeOrderedSet[T]) Disjoint(other Set[T]) bool {
+	return disjointByContains[T](s, other)
+}
+
+func (s *unsafeOrderedSet[T]) IntersectWith(other Set[T]) {
+	if other == Set[T](s) {
+		return
+	}
+	s.EachMutable(func(elem T) bool {
+		return other.Contains(elem)
+	})
+}
+
+func (s *unsafeOrderedSet[T]) IsSubset(other Set[T]) bool {
+	return isSubsetByContains[T](s, other)
+}
+
+func (s *unsafeOrderedSet[T]) IsProperSubset(other Set[T]) bool {
+	return s.Len() < other.Len() && s.IsSubset(other)
+}
+
+func (s *unsafeOrderedSet[T]) IsSuperset(other Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+func (s *unsafeOrderedSet[T]) IsProperSuperset(other Set[T]) bool {
+	return s.Len() > other.Len() && s.IsSuperset(other)
+}
+
+func (s *unsafeOrderedSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *unsafeOrderedSet[T]) IterContext(ctx context.Context) <-chan T {
+	return iterContext(ctx, s.Len(), s.Each)
+}
+
+func (s *unsafeOrderedSet[T]) Iterator() iter.Seq[T] {
+	return s.Each
+}
+
+func (s *unsafeOrderedSet[T]) Consume() <-chan T {
+	elems := s.ToSlice()
+	s.Clear()
+	return sliceChan(elems)
+}
+
+// Pop removes the earliest added element.
+func (s *unsafeOrderedSet[T]) Pop() (T, bool) {
+	if s.head == nil {
+		var zeroElem T
+		return zeroElem, false
+	}
+	elem := s.head.elem
+	s.remove(elem)
+	return elem, true
+}
+
+// PopN removes the n earliest added elements.
+func (s *unsafeOrderedSet[T]) PopN(n int) []T {
+	popped := make([]T, 0, max(min(n, s.Len()), 0))
+	for len(popped) < n {
+		elem, ok := s.Pop()
+		if !ok {
+			break
+		}
+		popped = append(popped, elem)
+	}
+	return popped
+}
+
+func (s *unsafeOrderedSet[T]) Sample() (T, bool) {
+	return sampleOf(s.Each)
+}
+
+func (s *unsafeOrderedSet[T]) SampleN(n int) []T {
+	return sampleNOf(s.Len(), s.Each, n)
+}
+
+func (s *unsafeOrderedSet[T]) Remove(v ...T) {
+	for _, val := range v {
+		s.remove(val)
+	}
+}
+
+func (s *unsafeOrderedSet[T]) RemoveSet(other Set[T]) {
+	if other == Set[T](s) {
+		s.Clear()
+		return
+	}
+	other.Each(func(elem T) bool {
+		s.remove(elem)
+		return true
+	})
+}
+
+func (s *unsafeOrderedSet[T]) RemoveIf(pred func(T) bool) int {
+	return removeIfByEachMutable[T](s, pred)
+}
+
+func (s *unsafeOrderedSet[T]) ReplaceAll(items []T) {
+	s.Clear()
+	s.Add(items...)
+}
+
+// Toggle appends the elements it adds after all existing elements.
+func (s *unsafeOrderedSet[T]) Toggle(v ...T) int {
+	prevLen := s.Len()
+	for _, val := range v {
+		if !s.remove(val) {
+			s.add(val)
+		}
+	}
+	return s.Len() - prevLen
+}
+
+// Union orders the elements of this set first, followed by those only in other.
+func (s *unsafeOrderedSet[T]) Union(other Set[T]) Set[T] {
+	return unionByContains[T](newUnsafeOrderedSet[T](), s, other)
+}
+
+func (s *unsafeOrderedSet[T]) UnionWith(other Set[T]) bool {
+	return s.AddSet(other)
+}
+
+func (s *unsafeOrderedSet[T]) ToSlice() []T {
+	return s.AppendTo(make([]T, 0, s.Len()))
+}
+
+func (s *unsafeOrderedSet[T]) ToSortedSlice(less func(a, b T) bool) []T {
+	return sortedSlice(s.ToSlice(), less)
+}
+
+func (s *unsafeOrderedSet[T]) AppendTo(dst []T) []T {
+	s.Each(func(elem T) bool {
+		dst = append(dst, elem)
+		return true
+	})
+	return dst
+}
+
+func (s *unsafeOrderedSet[T]) String() string {
+	var items []string
+	s.Each(func(elem T) bool {
+		items = append(items, fmt.Sprintf("%#v", elem))
+		return true
+	})
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
+
+func (s *unsafeOrderedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *unsafeOrderedSet[T]) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalJSONElems[T](data)
+	if err != nil {
+		return err
+	}
+	if s.nodes == nil {
+		s.nodes = make(map[T]*orderedNode[T], len(elems))
+	}
+	s.Add(elems...)
+	return nil
+}
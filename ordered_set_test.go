@@ -0,0 +1,70 @@
+package goset_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestOrderedSet(t *testing.T) {
+	set := goset.NewThreadUnsafeOrderedSet("c", "a", "d", "b")
+	assert.EqualValues(t, []string{"c", "a", "d", "b"}, set.ToSlice())
+
+	// re-adding an element that is present does not move it
+	assert.False(t, set.Add("a"))
+	assert.True(t, set.Add("e", "c"))
+	assert.EqualValues(t, []string{"c", "a", "d", "b", "e"}, set.ToSlice())
+
+	// removing from the head, middle and tail relinks the remaining elements
+	set.Remove("c", "d", "e")
+	assert.EqualValues(t, []string{"a", "b"}, set.ToSlice())
+	assert.False(t, set.Contains("c"))
+	assert.Equal(t, 2, set.Len())
+
+	// a removed element goes to the back when added again
+	set.Add("c", "a", "d")
+	assert.EqualValues(t, []string{"a", "b", "c", "d"}, set.ToSlice())
+	assert.Equal(t, `Set{"a", "b", "c", "d"}`, set.String())
+
+	var iterated []string
+	for elem := range set.Iter() {
+		iterated = append(iterated, elem)
+	}
+	assert.EqualValues(t, []string{"a", "b", "c", "d"}, iterated)
+
+	set.EachMutable(func(elem string) bool { return elem != "b" })
+	assert.EqualValues(t, []string{"a", "c", "d"}, set.ToSlice())
+
+	assert.Equal(t, 1, set.Toggle("a", "b", "e"))
+	assert.EqualValues(t, []string{"c", "d", "b", "e"}, set.ToSlice())
+
+	v, ok := set.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, "c", v)
+	assert.EqualValues(t, []string{"d", "b"}, set.PopN(2))
+	assert.EqualValues(t, []string{"e"}, set.ToSlice())
+
+	set.Remove("e")
+	_, ok = set.Pop()
+	assert.False(t, ok)
+	set.Add("x", "y")
+	assert.EqualValues(t, []string{"x", "y"}, set.ToSlice())
+}
+
+func TestOrderedSetOperationsKeepOrder(t *testing.T) {
+	a := goset.NewOrderedSet(5, 1, 4, 2)
+	b := goset.NewOrderedSet(3, 4, 6, 1)
+
+	assert.EqualValues(t, []int{5, 2}, a.Diff(b).ToSlice())
+	assert.EqualValues(t, []int{5, 1, 4, 2, 3, 6}, a.Union(b).ToSlice())
+	assert.EqualValues(t, []int{5, 2, 3, 6}, a.SymmetricDiff(b).ToSlice())
+	assert.EqualValues(t, []int{5, 4}, a.Filter(func(v int) bool { return v > 3 }).ToSlice())
+	assert.EqualValues(t, []int{5, 1, 4, 2}, a.Clone().ToSlice())
+
+	data, err := json.Marshal(a)
+	assert.NoError(t, err)
+	assert.Equal(t, "[5,1,4,2]", string(data))
+}
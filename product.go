@@ -0,0 +1,46 @@
+package goset
+
+import (
+	"iter"
+)
+
+// Pair holds one element from each of two sets.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// ProductContains returns a boolean indicating if the pair (x, y) is in the Cartesian product of a and b,
+// without building the product.
+func ProductContains[A comparable, B comparable](a Set[A], b Set[B], x A, y B) bool {
+	return a.Contains(x) && b.Contains(y)
+}
+
+// ProductSeq returns an iterator over the Cartesian product of a and b that yields pairs one at a time,
+// so large products can be streamed without building them. Iteration stops when yield returns false.
+// The elements of b are copied when iteration starts, so the loop body may mutate b, but a is iterated in place:
+// a thread-safe a stays read locked for the duration of the loop, which must not mutate a.
+func ProductSeq[A comparable, B comparable](a Set[A], b Set[B]) iter.Seq[Pair[A, B]] {
+	return func(yield func(Pair[A, B]) bool) {
+		// copying b also keeps a thread-safe set from being read locked twice when a and b are the same set
+		second := b.ToSlice()
+		a.Each(func(x A) bool {
+			for _, y := range second {
+				if !yield(Pair[A, B]{First: x, Second: y}) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+// Product returns a thread-safe set of every pair (x, y) with x in a and y in b, the Cartesian product of a and b.
+// It holds a.Len() * b.Len() pairs; use ProductSeq to stream a large product instead.
+func Product[A comparable, B comparable](a Set[A], b Set[B]) Set[Pair[A, B]] {
+	product := NewSetWithCapacity[Pair[A, B]](a.Len() * b.Len())
+	for pair := range ProductSeq(a, b) {
+		product.Add(pair)
+	}
+	return product
+}
@@ -0,0 +1,118 @@
+package goset_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestProductContains(t *testing.T) {
+	a := goset.NewSet(1, 2)
+	b := goset.NewSet("a", "b", "c")
+
+	assert.True(t, goset.ProductContains(a, b, 1, "c"))
+	assert.False(t, goset.ProductContains(a, b, 3, "a"))
+	assert.False(t, goset.ProductContains(a, b, 2, "d"))
+}
+
+func TestProductSeq(t *testing.T) {
+	a := goset.NewSet(1, 2)
+	b := goset.NewSet("a", "b", "c")
+
+	var pairs []goset.Pair[int, string]
+	goset.ProductSeq(a, b)(func(p goset.Pair[int, string]) bool {
+		pairs = append(pairs, p)
+		return true
+	})
+	assert.Len(t, pairs, 6)
+	for _, p := range pairs {
+		assert.True(t, goset.ProductContains(a, b, p.First, p.Second))
+	}
+	assert.Len(t, goset.NewThreadUnsafeSet(pairs...).ToSlice(), 6)
+
+	count := 0
+	for range goset.ProductSeq(a, b) {
+		count++
+		if count == 4 {
+			break
+		}
+	}
+	assert.Equal(t, 4, count)
+}
+
+func TestProductSeqOfSetWithItself(t *testing.T) {
+	// finishes reports whether fn returns within a few seconds rather than deadlocking
+	finishes := func(fn func()) bool {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			fn()
+		}()
+		select {
+		case <-done:
+			return true
+		case <-time.After(5 * time.Second):
+			return false
+		}
+	}
+
+	set := goset.NewSet[int]()
+	for i := 0; i < 100; i++ {
+		set.Add(i)
+	}
+	assert.True(t, finishes(func() {
+		stop := make(chan struct{})
+		writerDone := make(chan struct{})
+		go func() {
+			defer close(writerDone)
+			for i := 100; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+					set.Add(i)
+				}
+			}
+		}()
+		for i := 0; i < 20; i++ {
+			for range goset.ProductSeq(set, set) {
+			}
+		}
+		close(stop)
+		<-writerDone
+	}), "ProductSeq(s, s) deadlocked with a concurrent writer")
+
+	// the loop body may mutate the second set
+	a := goset.NewSet(1, 2)
+	b := goset.NewSet("a")
+	count := 0
+	if !assert.True(t, finishes(func() {
+		for p := range goset.ProductSeq(a, b) {
+			b.Add(p.Second + "!")
+			count++
+		}
+	}), "mutating the second set from the loop body deadlocked") {
+		return
+	}
+	assert.Equal(t, 2, count)
+	assert.ElementsMatch(t, []string{"a", "a!"}, b.ToSlice())
+}
+
+func TestProduct(t *testing.T) {
+	a := goset.NewSet(1, 2)
+	b := goset.NewThreadUnsafeSet("a", "b", "c")
+
+	product := goset.Product(a, b)
+	assert.Equal(t, 6, product.Len())
+	for _, x := range []int{1, 2} {
+		for _, y := range []string{"a", "b", "c"} {
+			assert.True(t, product.Contains(goset.Pair[int, string]{First: x, Second: y}))
+		}
+	}
+	assert.False(t, product.Contains(goset.Pair[int, string]{First: 3, Second: "a"}))
+
+	assert.Zero(t, goset.Product(a, goset.NewSet[string]()).Len())
+}
@@ -0,0 +1,26 @@
+package goset
+
+// InAllButOne returns the elements present in at least N-1 of the N given sets, which is useful for quorum analysis
+// where a single member may fail. With fewer than two sets it returns their union.
+func InAllButOne[T comparable](sets ...Set[T]) Set[T] {
+	return atLeastK(len(sets)-1, sets...)
+}
+
+// atLeastK returns the elements present in at least k of the given sets. A k below one yields their union.
+func atLeastK[T comparable](k int, sets ...Set[T]) Set[T] {
+	counts := make(map[T]int)
+	for _, set := range sets {
+		set.Each(func(elem T) bool {
+			counts[elem]++
+			return true
+		})
+	}
+
+	result := NewSet[T]()
+	for elem, count := range counts {
+		if count >= k {
+			result.Add(elem)
+		}
+	}
+	return result
+}
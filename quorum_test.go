@@ -0,0 +1,22 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestInAllButOne(t *testing.T) {
+	a := goset.NewSet(1, 2, 3)
+	b := goset.NewSet(1, 2)
+	c := goset.NewThreadUnsafeSet(1, 4)
+
+	// 1 is in every set, 2 is missing from one set, 3 and 4 are missing from two.
+	assert.True(t, goset.NewSet(1, 2).Equal(goset.InAllButOne(a, b, c)))
+
+	assert.True(t, goset.NewSet(1, 2, 3).Equal(goset.InAllButOne(a)))
+	assert.True(t, goset.NewSet(1, 2, 3, 4).Equal(goset.InAllButOne(a, c)))
+	assert.Equal(t, 0, goset.InAllButOne[int]().Len())
+}
@@ -0,0 +1,130 @@
+package goset
+
+import (
+	"context"
+)
+
+// Limiter throttles operations. *rate.Limiter from golang.org/x/time/rate satisfies this interface.
+type Limiter interface {
+	// Wait blocks until an operation is allowed, or returns an error if it never will be.
+	Wait(ctx context.Context) error
+}
+
+type rateLimitedSet[T any] struct {
+	Set[T]
+	limiter Limiter
+}
+
+// Assert concrete type:rateLimitedSet adheres to Set interface.
+var _ Set[int] = (*rateLimitedSet[int])(nil)
+
+// NewRateLimitedSet returns a set that waits on the given limiter before every operation that changes the inner set,
+// EachMutable included. If the limiter returns an error the set is left unchanged. Reads go straight to the inner set.
+func NewRateLimitedSet[T any](inner Set[T], limiter Limiter) Set[T] {
+	return &rateLimitedSet[T]{
+		Set:     inner,
+		limiter: limiter,
+	}
+}
+
+func (s *rateLimitedSet[T]) Add(v ...T) bool {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		return false
+	}
+	return s.Set.Add(v...)
+}
+
+func (s *rateLimitedSet[T]) AddSet(other Set[T]) bool {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		return false
+	}
+	return s.Set.AddSet(other)
+}
+
+func (s *rateLimitedSet[T]) UnionWith(other Set[T]) bool {
+	return s.AddSet(other)
+}
+
+func (s *rateLimitedSet[T]) Remove(v ...T) {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		return
+	}
+	s.Set.Remove(v...)
+}
+
+func (s *rateLimitedSet[T]) RemoveSet(other Set[T]) {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		return
+	}
+	s.Set.RemoveSet(other)
+}
+
+func (s *rateLimitedSet[T]) RemoveIf(pred func(T) bool) int {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		return 0
+	}
+	return s.Set.RemoveIf(pred)
+}
+
+func (s *rateLimitedSet[T]) IntersectWith(other Set[T]) {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		return
+	}
+	s.Set.IntersectWith(other)
+}
+
+func (s *rateLimitedSet[T]) Clear() {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		return
+	}
+	s.Set.Clear()
+}
+
+// EachMutable waits on the limiter once before iterating and, if it returns an error, iterates without removing
+// any element.
+func (s *rateLimitedSet[T]) EachMutable(fn func(T) (keep bool)) {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		s.Set.Each(func(elem T) bool {
+			fn(elem)
+			return true
+		})
+		return
+	}
+	s.Set.EachMutable(fn)
+}
+
+func (s *rateLimitedSet[T]) Pop() (T, bool) {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		var zeroElem T
+		return zeroElem, false
+	}
+	return s.Set.Pop()
+}
+
+func (s *rateLimitedSet[T]) PopN(n int) []T {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		return []T{}
+	}
+	return s.Set.PopN(n)
+}
+
+func (s *rateLimitedSet[T]) Toggle(v ...T) int {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		return 0
+	}
+	return s.Set.Toggle(v...)
+}
+
+func (s *rateLimitedSet[T]) Consume() <-chan T {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		return sliceChan[T](nil)
+	}
+	return s.Set.Consume()
+}
+
+func (s *rateLimitedSet[T]) ReplaceAll(items []T) {
+	if err := s.limiter.Wait(context.Background()); err != nil {
+		return
+	}
+	s.Set.ReplaceAll(items)
+}
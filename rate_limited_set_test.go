@@ -0,0 +1,78 @@
+package goset_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+type tickLimiter struct {
+	ticker *time.Ticker
+}
+
+func (l *tickLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l.ticker.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type denyLimiter struct{}
+
+func (denyLimiter) Wait(context.Context) error {
+	return errors.New("denied")
+}
+
+func TestRateLimitedSet(t *testing.T) {
+	t.Run("Throttled", func(t *testing.T) {
+		interval := 10 * time.Millisecond
+		limiter := &tickLimiter{ticker: time.NewTicker(interval)}
+		defer limiter.ticker.Stop()
+
+		set := goset.NewRateLimitedSet(goset.NewSet[int](), limiter)
+		start := time.Now()
+		for i := 1; i <= 5; i++ {
+			set.Add(i)
+		}
+		set.Remove(5)
+		popped, _ := set.Pop()
+		set.Add(popped, 5)
+		set.EachMutable(func(v int) bool { return v != 5 })
+		assert.GreaterOrEqual(t, time.Since(start), 8*interval)
+
+		start = time.Now()
+		assert.True(t, set.Contains(1, 2, 3, 4))
+		assert.Equal(t, 4, set.Len())
+		assert.Less(t, time.Since(start), interval)
+
+		actualItems := set.ToSlice()
+		sort.Ints(actualItems)
+		assert.EqualValues(t, []int{1, 2, 3, 4}, actualItems)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		set := goset.NewRateLimitedSet(goset.NewSet(1, 2), denyLimiter{})
+		assert.False(t, set.Add(3))
+		set.Remove(1)
+		assert.Zero(t, set.RemoveIf(func(int) bool { return true }))
+		_, ok := set.Pop()
+		assert.False(t, ok)
+		set.Clear()
+		visited := 0
+		set.EachMutable(func(int) bool {
+			visited++
+			return false
+		})
+		assert.Equal(t, 2, visited)
+		assert.Equal(t, 2, set.Len())
+		assert.True(t, set.Contains(1, 2))
+	})
+}
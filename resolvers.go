@@ -0,0 +1,41 @@
+package goset
+
+// KeepFirst returns a Resolver that always keeps the item already in the set, which is what a nil Resolver does.
+func KeepFirst[T any]() Resolver[T] {
+	return func(foundItem, _ T) (T, bool) {
+		return foundItem, false
+	}
+}
+
+// KeepLast returns a Resolver that always replaces the item in the set with the newly added one.
+func KeepLast[T any]() Resolver[T] {
+	return func(_, newItem T) (T, bool) {
+		return newItem, true
+	}
+}
+
+// MaxResolver returns a Resolver that keeps the greater of two conflicting items according to less.
+// On a tie the item already in the set is kept.
+func MaxResolver[T any](less func(a, b T) bool) Resolver[T] {
+	return func(foundItem, newItem T) (T, bool) {
+		if less(foundItem, newItem) {
+			return newItem, true
+		}
+		return foundItem, false
+	}
+}
+
+// MinResolver returns a Resolver that keeps the lesser of two conflicting items according to less.
+// On a tie the item already in the set is kept.
+func MinResolver[T any](less func(a, b T) bool) Resolver[T] {
+	return MaxResolver(func(a, b T) bool { return less(b, a) })
+}
+
+// MergeResolver returns a Resolver that replaces the item in the set with the result of merging it with the newly
+// added one, for example to sum a field across items with the same key. merge must return an item with the same key
+// and should not modify its arguments.
+func MergeResolver[T any](merge func(foundItem, newItem T) T) Resolver[T] {
+	return func(foundItem, newItem T) (T, bool) {
+		return merge(foundItem, newItem), true
+	}
+}
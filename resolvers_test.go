@@ -0,0 +1,52 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestResolvers(t *testing.T) {
+	byID := func(item TestType) int { return item.ID }
+	byImportance := func(a, b TestType) bool { return a.Importance < b.Importance }
+	items := []TestType{
+		{ID: 1, Name: "One", Importance: 2},
+		{ID: 1, Name: "Uno", Importance: 5},
+		{ID: 1, Name: "Eins", Importance: 1},
+		{ID: 1, Name: "Un", Importance: 5},
+	}
+
+	testCases := []struct {
+		name     string
+		resolver goset.Resolver[TestType]
+		expected TestType
+	}{
+		{name: "KeepFirst", resolver: goset.KeepFirst[TestType](), expected: items[0]},
+		{name: "KeepLast", resolver: goset.KeepLast[TestType](), expected: items[3]},
+		{name: "MaxResolver", resolver: goset.MaxResolver(byImportance), expected: items[1]},
+		{name: "MinResolver", resolver: goset.MinResolver(byImportance), expected: items[2]},
+		{
+			name: "MergeResolver",
+			resolver: goset.MergeResolver(func(foundItem, newItem TestType) TestType {
+				foundItem.Importance += newItem.Importance
+				return foundItem
+			}),
+			expected: TestType{ID: 1, Name: "One", Importance: 13},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			set := goset.NewResolvingSet(byID, tc.resolver)
+			set.Add(items...)
+			set.Add(TestType{ID: 2, Name: "Two", Importance: 1})
+
+			assert.Equal(t, 2, set.Len())
+			winner, ok := set.Get(1)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, winner)
+		})
+	}
+}
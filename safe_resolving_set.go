@@ -1,176 +1,568 @@
 package goset
 
 import (
+	"context"
+	"encoding/json"
+	"iter"
 	"sync"
+	"unsafe"
 )
 
-type safeSet[T any, U comparable] struct {
+type safeSet[T any] struct {
 	sync.RWMutex
-	set Set[T]
+	set    Set[T]
+	filter prefilter[T]
 }
 
 // Assert concrete type:safeSet adheres to Set interface.
-var _ Set[int] = (*safeSet[int, string])(nil)
+var _ Set[int] = (*safeSet[int])(nil)
 
-func newSafeResolvingSet[T any, U comparable](keyGetter KeyGetter[T, U], comparator Resolver[T]) *safeSet[T, U] {
-	set := newUnsafeResolvingSet(keyGetter, comparator)
-	return &safeSet[T, U]{
-		set: set,
+// safeResolvingSet is a safeSet guarding a resolving set, which adds the methods of the ResolvingSet interface.
+// resolving is the same set as the embedded safeSet wraps.
+type safeResolvingSet[T any, U comparable] struct {
+	safeSet[T]
+	resolving *unsafeResolvingSet[T, U]
+}
+
+// Assert concrete type:safeResolvingSet adheres to ResolvingSet interface.
+var _ ResolvingSet[int, string] = (*safeResolvingSet[int, string])(nil)
+
+// lockableSet is implemented by every thread-safe set of elements of type T, whether or not it is a resolving set,
+// so that an operation between two of them can lock both and work on the sets they wrap.
+type lockableSet[T any] interface {
+	Set[T]
+	RLock()
+	RUnlock()
+	// wrapped returns the thread-unsafe set guarded by the lock.
+	wrapped() Set[T]
+	// lockRank orders the locks of two sets, which are always taken lowest rank first to avoid deadlocks between
+	// operations running in opposite directions, such as a.Intersect(b) and b.Intersect(a).
+	lockRank() uintptr
+}
+
+func newSafeResolvingSet[T any, U comparable](keyGetter KeyGetter[T, U], comparator Resolver[T]) *safeResolvingSet[T, U] {
+	return wrapResolving(newUnsafeResolvingSet(keyGetter, comparator))
+}
+
+// wrapResolving returns a thread-safe resolving set guarding set.
+func wrapResolving[T any, U comparable](set *unsafeResolvingSet[T, U]) *safeResolvingSet[T, U] {
+	return &safeResolvingSet[T, U]{
+		safeSet:   safeSet[T]{set: set},
+		resolving: set,
 	}
 }
 
-func newSafeSimpleSet[T comparable]() *safeSet[T, struct{}] {
+func newSafeSimpleSet[T comparable]() *safeSet[T] {
 	set := newUnsafeSimpleSet[T]()
-	return &safeSet[T, struct{}]{
+	return &safeSet[T]{
 		set: set,
 	}
 }
 
-func (s *safeSet[T, U]) Add(v ...T) bool {
+// threadSafeWrapper is implemented by thread-unsafe sets that have a thread-safe wrapper of their own.
+type threadSafeWrapper[T any] interface {
+	threadSafe() Set[T]
+}
+
+// wrapThreadSafe returns a thread-safe set guarding set, which is a resolving set when set is one, so that the sets
+// returned by a thread-safe resolving set are resolving sets too.
+func wrapThreadSafe[T any](set Set[T]) Set[T] {
+	if w, ok := set.(threadSafeWrapper[T]); ok {
+		return w.threadSafe()
+	}
+	return &safeSet[T]{set: set}
+}
+
+func (s *safeSet[T]) wrapped() Set[T] {
+	return s.set
+}
+
+func (s *safeSet[T]) lockRank() uintptr {
+	return uintptr(unsafe.Pointer(s))
+}
+
+// lockWith write locks this set, and read locks the other set when it is a different lockableSet, returning the set
+// to operate on in place of other and a function releasing the locks. Other implementations are cloned before the
+// lock is taken, since they may read this set, as a frozen or mirrored view of it does, and would deadlock on its lock.
+func (s *safeSet[T]) lockWith(other Set[T]) (Set[T], func()) {
+	o, ok := other.(lockableSet[T])
+	if !ok {
+		snapshot := other.Clone()
+		s.Lock()
+		return snapshot, s.Unlock
+	}
+	if o.lockRank() == s.lockRank() {
+		s.Lock()
+		return s.set, s.Unlock
+	}
+	if o.lockRank() < s.lockRank() {
+		o.RLock()
+		s.Lock()
+	} else {
+		s.Lock()
+		o.RLock()
+	}
+	return o.wrapped(), func() {
+		o.RUnlock()
+		s.Unlock()
+	}
+}
+
+func (s *safeSet[T]) Add(v ...T) bool {
 	s.Lock()
 	defer s.Unlock()
+	if s.filter != nil {
+		s.filter.add(v...)
+	}
 	return s.set.Add(v...)
 }
 
-func (s *safeSet[T, U]) Len() int {
+func (s *safeSet[T]) AddSet(other Set[T]) bool {
+	other, unlock := s.lockWith(other)
+	defer unlock()
+	if s.filter != nil {
+		other.Each(func(elem T) bool {
+			s.filter.add(elem)
+			return true
+		})
+	}
+	return s.set.AddSet(other)
+}
+
+func (s *safeSet[T]) WouldAdd(v T) bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.set.WouldAdd(v)
+}
+
+func (s *safeSet[T]) Len() int {
 	s.RLock()
 	defer s.RUnlock()
 	return s.set.Len()
 }
 
-func (s *safeSet[T, U]) Clear() {
+func (s *safeSet[T]) IsEmpty() bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.set.IsEmpty()
+}
+
+func (s *safeSet[T]) Stats() SetStats {
+	s.RLock()
+	defer s.RUnlock()
+	return s.set.Stats()
+}
+
+func (s *safeSet[T]) Clear() {
 	s.Lock()
 	defer s.Unlock()
+	if s.filter != nil {
+		s.filter.reset()
+	}
 	s.set.Clear()
 }
 
-func (s *safeSet[T, U]) Clone() Set[T] {
+func (s *safeSet[T]) Clone() Set[T] {
 	s.RLock()
 	defer s.RUnlock()
 	unsafeClone := s.set.Clone()
-	return &safeSet[T, U]{set: unsafeClone}
+	return wrapThreadSafe(unsafeClone)
 }
 
-func (s *safeSet[T, U]) Contains(v ...T) bool {
+func (s *safeSet[T]) CloneWith(copy func(T) T) Set[T] {
+	s.RLock()
+	defer s.RUnlock()
+	unsafeClone := s.set.CloneWith(copy)
+	return wrapThreadSafe(unsafeClone)
+}
+
+func (s *safeSet[T]) Contains(v ...T) bool {
+	if s.filter != nil {
+		for _, val := range v {
+			if !s.filter.mayContain(val) {
+				return false
+			}
+		}
+	}
 	s.RLock()
 	defer s.RUnlock()
 	return s.set.Contains(v...)
 }
 
-func (s *safeSet[T, U]) Each(fn func(T) bool) {
+func (s *safeSet[T]) ContainsAny(v ...T) bool {
+	if s.filter != nil {
+		var candidates []T
+		for _, val := range v {
+			if s.filter.mayContain(val) {
+				candidates = append(candidates, val)
+			}
+		}
+		v = candidates
+	}
+	if len(v) == 0 {
+		return false
+	}
 	s.RLock()
 	defer s.RUnlock()
-	s.set.Each(fn)
+	return s.set.ContainsAny(v...)
 }
 
-func (s *safeSet[T, U]) Diff(other Set[T]) Set[T] {
-	o := other.(*safeSet[T, U])
+func (s *safeSet[T]) Each(fn func(T) bool) {
 	s.RLock()
-	o.RLock()
 	defer s.RUnlock()
-	defer o.RUnlock()
+	s.set.Each(fn)
+}
+
+func (s *safeSet[T]) EachMutable(fn func(T) (keep bool)) {
+	s.Lock()
+	defer s.Unlock()
+	s.set.EachMutable(fn)
+}
 
-	unsafeDiff := s.set.Diff(o.set)
-	return &safeSet[T, U]{set: unsafeDiff}
+func (s *safeSet[T]) EachSnapshot(fn func(T) bool) {
+	eachOf(s.ToSlice(), fn)
 }
 
-func (s *safeSet[T, U]) SymmetricDiff(other Set[T]) Set[T] {
-	o := other.(*safeSet[T, U])
+func (s *safeSet[T]) Filter(pred func(T) bool) Set[T] {
 	s.RLock()
-	o.RLock()
 	defer s.RUnlock()
-	defer o.RUnlock()
+	unsafeFiltered := s.set.Filter(pred)
+	return wrapThreadSafe(unsafeFiltered)
+}
+
+func (s *safeSet[T]) Partition(pred func(T) bool) (Set[T], Set[T]) {
+	s.RLock()
+	defer s.RUnlock()
+	unsafeMatching, unsafeRest := s.set.Partition(pred)
+	return wrapThreadSafe(unsafeMatching), wrapThreadSafe(unsafeRest)
+}
+
+func (s *safeSet[T]) Any(pred func(T) bool) bool {
+	return anyOf(s.Each, pred)
+}
+
+func (s *safeSet[T]) All(pred func(T) bool) bool {
+	return allOf(s.Each, pred)
+}
+
+// rlockWith read locks this set, and the other set when it is a different lockableSet, returning the set to operate
+// on in place of other and a function releasing the locks. Other implementations are used through the Set interface.
+func (s *safeSet[T]) rlockWith(other Set[T]) (Set[T], func()) {
+	o, ok := other.(lockableSet[T])
+	if !ok {
+		s.RLock()
+		return other, s.RUnlock
+	}
+	if o.lockRank() == s.lockRank() {
+		s.RLock()
+		return s.set, s.RUnlock
+	}
+	first, second := lockableSet[T](s), o
+	if o.lockRank() < s.lockRank() {
+		first, second = o, s
+	}
+	first.RLock()
+	second.RLock()
+	return o.wrapped(), func() {
+		second.RUnlock()
+		first.RUnlock()
+	}
+}
+
+func (s *safeSet[T]) Diff(other Set[T]) Set[T] {
+	o, unlock := s.rlockWith(other)
+	defer unlock()
+
+	unsafeDiff := s.set.Diff(o)
+	return wrapThreadSafe(unsafeDiff)
+}
+
+func (s *safeSet[T]) DiffLen(other Set[T]) int {
+	o, unlock := s.rlockWith(other)
+	defer unlock()
+
+	return s.set.DiffLen(o)
+}
+
+func (s *safeSet[T]) SymmetricDiff(other Set[T]) Set[T] {
+	o, unlock := s.rlockWith(other)
+	defer unlock()
+
+	unsafeDiff := s.set.SymmetricDiff(o)
+	return wrapThreadSafe(unsafeDiff)
+}
+
+func (s *safeSet[T]) Equal(other Set[T]) bool {
+	o, unlock := s.rlockWith(other)
+	defer unlock()
+
+	return s.set.Equal(o)
+}
+
+func (s *safeSet[T]) Intersect(other Set[T]) Set[T] {
+	o, unlock := s.rlockWith(other)
+	defer unlock()
 
-	unsafeDiff := s.set.SymmetricDiff(o.set)
-	return &safeSet[T, U]{set: unsafeDiff}
+	unsafeIntersection := s.set.Intersect(o)
+	return wrapThreadSafe(unsafeIntersection)
 }
 
-func (s *safeSet[T, U]) Equal(other Set[T]) bool {
-	o := other.(*safeSet[T, U])
+func (s *safeSet[T]) IntersectLen(other Set[T]) int {
+	o, unlock := s.rlockWith(other)
+	defer unlock()
+
+	return s.set.IntersectLen(o)
+}
+
+func (s *safeSet[T]) Disjoint(other Set[T]) bool {
+	o, unlock := s.rlockWith(other)
+	defer unlock()
+
+	return s.set.Disjoint(o)
+}
+
+func (s *safeSet[T]) IntersectWith(other Set[T]) {
+	other, unlock := s.lockWith(other)
+	defer unlock()
+	s.set.IntersectWith(other)
+}
+
+func (s *safeSet[T]) IsSubset(other Set[T]) bool {
+	o, unlock := s.rlockWith(other)
+	defer unlock()
+
+	return s.set.IsSubset(o)
+}
+
+func (s *safeSet[T]) IsProperSubset(other Set[T]) bool {
+	o, unlock := s.rlockWith(other)
+	defer unlock()
+
+	return s.set.IsProperSubset(o)
+}
+
+func (s *safeSet[T]) IsSuperset(other Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+func (s *safeSet[T]) IsProperSuperset(other Set[T]) bool {
+	return other.IsProperSubset(s)
+}
+
+func (s *safeSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *safeSet[T]) IterContext(ctx context.Context) <-chan T {
+	return iterContext(ctx, s.Len(), s.Each)
+}
+
+func (s *safeSet[T]) Iterator() iter.Seq[T] {
+	return s.Each
+}
+
+func (s *safeSet[T]) Consume() <-chan T {
+	s.Lock()
+	defer s.Unlock()
+	return s.set.Consume()
+}
+
+func (s *safeSet[T]) Pop() (T, bool) {
+	s.Lock()
+	defer s.Unlock()
+	return s.set.Pop()
+}
+
+func (s *safeSet[T]) PopN(n int) []T {
+	s.Lock()
+	defer s.Unlock()
+	return s.set.PopN(n)
+}
+
+func (s *safeSet[T]) Sample() (T, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.set.Sample()
+}
+
+func (s *safeSet[T]) SampleN(n int) []T {
 	s.RLock()
-	o.RLock()
 	defer s.RUnlock()
-	defer o.RUnlock()
+	return s.set.SampleN(n)
+}
+
+func (s *safeSet[T]) Remove(v ...T) {
+	s.Lock()
+	defer s.Unlock()
+	s.set.Remove(v...)
+}
+
+func (s *safeSet[T]) RemoveSet(other Set[T]) {
+	other, unlock := s.lockWith(other)
+	defer unlock()
+	s.set.RemoveSet(other)
+}
+
+func (s *safeSet[T]) RemoveIf(pred func(T) bool) int {
+	s.Lock()
+	defer s.Unlock()
+	return s.set.RemoveIf(pred)
+}
+
+// ReplaceAll doesn't reset the prefilter, since Contains reads it without the lock and would miss elements that are
+// both replaced and kept. The bits left by replaced elements only cost false positives until the set is cleared.
+func (s *safeSet[T]) ReplaceAll(items []T) {
+	s.Lock()
+	defer s.Unlock()
+	if s.filter != nil {
+		s.filter.add(items...)
+	}
+	s.set.ReplaceAll(items)
+}
+
+func (s *safeSet[T]) Toggle(v ...T) int {
+	s.Lock()
+	defer s.Unlock()
+	if s.filter != nil {
+		s.filter.add(v...)
+	}
+	return s.set.Toggle(v...)
+}
+
+func (s *safeSet[T]) Union(other Set[T]) Set[T] {
+	o, unlock := s.rlockWith(other)
+	defer unlock()
+
+	unsafeUnion := s.set.Union(o)
+	return wrapThreadSafe(unsafeUnion)
+}
 
-	return s.set.Equal(o.set)
+func (s *safeSet[T]) UnionWith(other Set[T]) bool {
+	return s.AddSet(other)
 }
 
-func (s *safeSet[T, U]) Intersect(other Set[T]) Set[T] {
-	o := other.(*safeSet[T, U])
+func (s *safeSet[T]) ToSlice() []T {
 	s.RLock()
-	o.RLock()
 	defer s.RUnlock()
-	defer o.RUnlock()
+	return s.set.ToSlice()
+}
 
-	unsafeIntersection := s.set.Intersect(o.set)
-	return &safeSet[T, U]{set: unsafeIntersection}
+func (s *safeSet[T]) ToSortedSlice(less func(a, b T) bool) []T {
+	s.RLock()
+	defer s.RUnlock()
+	return s.set.ToSortedSlice(less)
 }
 
-func (s *safeSet[T, U]) IsSubset(other Set[T]) bool {
-	o := other.(*safeSet[T, U])
+func (s *safeSet[T]) AppendTo(dst []T) []T {
 	s.RLock()
-	o.RLock()
 	defer s.RUnlock()
-	defer o.RUnlock()
+	return s.set.AppendTo(dst)
+}
 
-	return s.set.IsSubset(o.set)
+func (s *safeSet[T]) String() string {
+	s.RLock()
+	defer s.RUnlock()
+	return s.set.String()
 }
 
-func (s *safeSet[T, U]) IsProperSubset(other Set[T]) bool {
-	o := other.(*safeSet[T, U])
+func (s *safeSet[T]) MarshalJSON() ([]byte, error) {
 	s.RLock()
-	o.RLock()
 	defer s.RUnlock()
-	defer o.RUnlock()
+	return json.Marshal(s.set.AppendTo(make([]T, 0, s.set.Len())))
+}
 
-	return s.set.IsProperSubset(o.set)
+func (s *safeSet[T]) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalJSONElems[T](data)
+	if err != nil {
+		return err
+	}
+	s.Add(elems...)
+	return nil
 }
 
-func (s *safeSet[T, U]) IsSuperset(other Set[T]) bool {
-	return other.IsSubset(s)
+func (s *safeSet[T]) GobEncode() ([]byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return gobEncodeElems(s.set.AppendTo(make([]T, 0, s.set.Len())))
 }
 
-func (s *safeSet[T, U]) IsProperSuperset(other Set[T]) bool {
-	return other.IsProperSubset(s)
+// GobDecode replaces the contents of the set with the decoded elements.
+func (s *safeSet[T]) GobDecode(data []byte) error {
+	elems, err := gobDecodeElems[T](data)
+	if err != nil {
+		return err
+	}
+	s.ReplaceAll(elems)
+	return nil
 }
 
-func (s *safeSet[T, U]) Iter() <-chan T {
+func (s *safeSet[T]) MarshalBinary() ([]byte, error) {
 	s.RLock()
 	defer s.RUnlock()
-	return s.set.Iter()
+	return marshalBinaryElems(s.set.AppendTo(make([]T, 0, s.set.Len())))
+}
+
+// UnmarshalBinary replaces the contents of the set with the decoded elements.
+func (s *safeSet[T]) UnmarshalBinary(data []byte) error {
+	elems, err := unmarshalBinaryElems[T](data)
+	if err != nil {
+		return err
+	}
+	s.ReplaceAll(elems)
+	return nil
 }
 
-func (s *safeSet[T, U]) Pop() (T, bool) {
+func (s *safeResolvingSet[T, U]) Update(v T) bool {
 	s.Lock()
 	defer s.Unlock()
-	return s.set.Pop()
+	if s.filter != nil {
+		s.filter.add(v)
+	}
+	return s.resolving.Update(v)
+}
+
+func (s *safeResolvingSet[T, U]) Upsert(v T) (T, bool) {
+	stored, _, _, replaced := s.upsert(v)
+	return stored, replaced
 }
 
-func (s *safeSet[T, U]) Remove(v ...T) {
+func (s *safeResolvingSet[T, U]) upsert(v T) (stored T, previous T, existed bool, replaced bool) {
 	s.Lock()
 	defer s.Unlock()
-	s.set.Remove(v...)
+	if s.filter != nil {
+		s.filter.add(v)
+	}
+	return s.resolving.upsert(v)
 }
 
-func (s *safeSet[T, U]) Union(other Set[T]) Set[T] {
-	o := other.(*safeSet[T, U])
+func (s *safeResolvingSet[T, U]) CloneTyped() ResolvingSet[T, U] {
 	s.RLock()
-	o.RLock()
 	defer s.RUnlock()
-	defer o.RUnlock()
+	return wrapResolving(s.resolving.clone())
+}
 
-	return s.set.Union(o.set)
+func (s *safeResolvingSet[T, U]) KeyFunc() KeyGetter[T, U] {
+	return s.resolving.KeyFunc()
+}
 
+func (s *safeResolvingSet[T, U]) ResolverFunc() Resolver[T] {
+	return s.resolving.ResolverFunc()
 }
 
-func (s *safeSet[T, U]) ToSlice() []T {
-	s.RLock()
-	defer s.RUnlock()
-	return s.set.ToSlice()
+func (s *safeResolvingSet[T, U]) RemoveReturning(v ...T) []T {
+	s.Lock()
+	defer s.Unlock()
+	return s.resolving.RemoveReturning(v...)
 }
 
-func (s *safeSet[T, U]) String() string {
+func (s *safeResolvingSet[T, U]) Get(key U) (T, bool) {
 	s.RLock()
 	defer s.RUnlock()
-	return s.set.String()
+	return s.resolving.Get(key)
+}
+
+func (s *safeResolvingSet[T, U]) RemoveKey(keys ...U) {
+	s.Lock()
+	defer s.Unlock()
+	s.resolving.RemoveKey(keys...)
 }
@@ -0,0 +1,82 @@
+package goset
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// StableSample returns a deterministic subset of s containing roughly the given fraction of its elements.
+// An element is included when its hash falls below fraction of the hash space, so the same elements
+// are selected on every run without a random number generator. A fraction of 0 or less selects nothing
+// and a fraction of 1 or more selects everything.
+func StableSample[T comparable](s Set[T], fraction float64, hash func(T) uint64) Set[T] {
+	sample := NewSet[T]()
+	if fraction <= 0 {
+		return sample
+	}
+	if fraction >= 1 {
+		sample.Add(s.ToSlice()...)
+		return sample
+	}
+
+	threshold := uint64(fraction * math.MaxUint64)
+	s.Each(func(elem T) bool {
+		if hash(elem) < threshold {
+			sample.Add(elem)
+		}
+		return true
+	})
+	return sample
+}
+
+// SeededSlice returns the elements of s in a pseudo-random order that is reproducible for a given seed.
+// The elements are sorted and then shuffled with a Fisher-Yates shuffle driven by the seed, so the order
+// does not depend on map iteration order the way ToSlice does.
+func SeededSlice[T cmp.Ordered](s Set[T], seed int64) []T {
+	elems := s.AppendTo(make([]T, 0, s.Len()))
+	slices.Sort(elems)
+
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	for i := len(elems) - 1; i > 0; i-- {
+		j := rng.IntN(i + 1)
+		elems[i], elems[j] = elems[j], elems[i]
+	}
+	return elems
+}
+
+// sampleOf returns an element visited by each chosen uniformly at random, using reservoir sampling so that no
+// snapshot of the elements is needed, and false if each visits nothing.
+func sampleOf[T any](each func(fn func(T) bool)) (T, bool) {
+	var sample T
+	seen := 0
+	each(func(elem T) bool {
+		seen++
+		if rand.IntN(seen) == 0 {
+			sample = elem
+		}
+		return true
+	})
+	return sample, seen > 0
+}
+
+// sampleNOf returns up to n of the size elements visited by each chosen uniformly at random, using reservoir
+// sampling.
+func sampleNOf[T any](size int, each func(fn func(T) bool), n int) []T {
+	if n <= 0 {
+		return []T{}
+	}
+	reservoir := make([]T, 0, min(n, size))
+	seen := 0
+	each(func(elem T) bool {
+		seen++
+		if len(reservoir) < n {
+			reservoir = append(reservoir, elem)
+		} else if j := rand.IntN(seen); j < n {
+			reservoir[j] = elem
+		}
+		return true
+	})
+	return reservoir
+}
@@ -0,0 +1,65 @@
+package goset_test
+
+import (
+	"hash/fnv"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func hashInt(v int) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strconv.Itoa(v)))
+	return h.Sum64()
+}
+
+func TestStableSample(t *testing.T) {
+	set := goset.NewSet[int]()
+	for i := 0; i < 10000; i++ {
+		set.Add(i)
+	}
+
+	sampleA := goset.StableSample(set, 0.25, hashInt)
+	sampleB := goset.StableSample(set.Clone(), 0.25, hashInt)
+	assert.True(t, sampleA.Equal(sampleB))
+	assert.InDelta(t, 2500, sampleA.Len(), 250)
+	assert.True(t, sampleA.IsSubset(set))
+
+	assert.Zero(t, goset.StableSample(set, 0, hashInt).Len())
+	assert.Equal(t, set.Len(), goset.StableSample(set, 1, hashInt).Len())
+}
+
+func TestSeededSlice(t *testing.T) {
+	set := goset.NewSet[int]()
+	for i := 0; i < 100; i++ {
+		set.Add(i)
+	}
+
+	first := goset.SeededSlice(set, 42)
+	assert.Equal(t, first, goset.SeededSlice(set.Clone(), 42))
+	assert.ElementsMatch(t, set.ToSlice(), first)
+	assert.NotEqual(t, first, goset.SeededSlice(set, 43))
+
+	assert.Empty(t, goset.SeededSlice(goset.NewSet[int](), 42))
+}
+
+func TestSampleReachesEveryElement(t *testing.T) {
+	set := goset.NewSet(1, 2, 3, 4)
+
+	seen := goset.NewThreadUnsafeSet[int]()
+	for i := 0; i < 1000 && seen.Len() < set.Len(); i++ {
+		v, ok := set.Sample()
+		assert.True(t, ok)
+		seen.Add(v)
+	}
+	assert.True(t, seen.Equal(set))
+
+	seen.Clear()
+	for i := 0; i < 1000 && seen.Len() < set.Len(); i++ {
+		seen.Add(set.SampleN(1)...)
+	}
+	assert.True(t, seen.Equal(set))
+}
@@ -1,45 +1,140 @@
 package goset
 
+import (
+	"context"
+	"iter"
+	"sort"
+)
+
 type KeyGetter[T any, U comparable] func(v T) U
 
 // Resolver is a function that determines which item gets put into the set when items with conflicting keys are encountered.
-// it returns the resolved item and a boolean that determines if the found item should be replaced with the new one
+// it returns the resolved item and a boolean that determines if the found item should be replaced with the new one.
+// A nil Resolver never replaces, so the first item added for a key is kept.
 type Resolver[T any] func(foundItem, newItem T) (T, bool)
 
+// SetStats describes the size of a set and of the storage backing it, for tuning.
+type SetStats struct {
+	// Len is the number of elements in the set.
+	Len int
+	// IsEmpty reports whether the set has no elements.
+	IsEmpty bool
+	// MapBacked reports whether the elements are stored in a Go map.
+	MapBacked bool
+	// Capacity is the number of elements the backing slice or bitmap holds before it has to grow. It is zero for
+	// map-backed sets, because Go doesn't expose how many buckets a map has allocated.
+	Capacity int
+}
+
 // Set represents an unordered set of data the operations that can be applied to it.
 type Set[T any] interface {
 	// Add adds one or more elements to a set
 	Add(v ...T) bool
 
+	// AddSet adds every element of other to this set in place, returning whether the set changed.
+	// Resolving sets run each element through their resolver, as Add does.
+	AddSet(other Set[T]) bool
+
+	// WouldAdd returns a boolean indicating if Add(v) would change the set, without changing it.
+	// For resolving sets that includes v replacing an element with the same key when the resolver prefers v.
+	WouldAdd(v T) bool
+
 	// Len returns the number of elements in the set
 	Len() int
 
+	// IsEmpty returns a boolean indicating if the set has no elements, the same as Len() == 0.
+	IsEmpty() bool
+
+	// Stats returns the size of the set and of the storage backing it.
+	Stats() SetStats
+
 	// Clear removes all elements from the set, resulting in an empty set
 	Clear()
 
-	// Clone returns a copy of the set
+	// Clone returns a shallow copy of the set. The clone has its own backing storage, so adding elements to
+	// or removing elements from either set never affects the other, but the elements themselves are copied
+	// by assignment: a pointer element is shared, and changes made through it are seen by both sets.
+	// Clones of resolving sets share the keyGetter and resolver functions of the original.
+	// Use CloneWith to copy the elements too.
 	Clone() Set[T]
 
-	// Contains returns a boolean indicating if any of the given items are in the set
+	// CloneWith returns a copy of the set holding copy applied to each element, which allows deep copies of
+	// pointer elements. Resolving sets key each copied element with their keyGetter, so copy must preserve keys.
+	CloneWith(copy func(T) T) Set[T]
+
+	// Contains returns a boolean indicating if all of the given items are in the set
 	Contains(v ...T) bool
 
+	// ContainsAny returns a boolean indicating if any of the given items are in the set.
+	// It returns false when no items are given.
+	ContainsAny(v ...T) bool
+
 	// Each iterates over items in the set applying the given function on each element.
 	// Breaks iteration if the given function returns false
 	Each(fn func(T) bool)
 
+	// EachMutable iterates over items in the live set, removing each element for which the given function returns false.
+	// Unlike Each, which never changes the set, removals take effect during iteration.
+	EachMutable(fn func(T) (keep bool))
+
+	// EachSnapshot copies the elements of the set and then applies the given function on each copied element,
+	// breaking iteration if it returns false. Thread-safe sets release their lock before calling the function,
+	// so unlike Each, the function may add to or remove from the set. The price is the copy, and the function
+	// sees the elements as they were when EachSnapshot was called, not changes made since.
+	EachSnapshot(fn func(T) bool)
+
+	// Filter returns a new set of the same kind containing only the elements for which pred returns true.
+	// Filtered resolving sets keep the keyGetter and resolver of the original.
+	Filter(pred func(T) bool) Set[T]
+
+	// Partition returns two new sets of the same kind, splitting the elements by pred in a single pass: matching
+	// holds those for which pred returns true and rest the others. Partitioned resolving sets keep the keyGetter
+	// and resolver of the original.
+	Partition(pred func(T) bool) (matching Set[T], rest Set[T])
+
+	// Any returns a boolean indicating if pred returns true for any element, stopping at the first such element.
+	// Any is false for an empty set.
+	Any(pred func(T) bool) bool
+
+	// All returns a boolean indicating if pred returns true for every element, stopping at the first element
+	// for which it returns false. All is true for an empty set.
+	All(pred func(T) bool) bool
+
 	// Diff returns a new set containing all items in this set, but not in the other
 	Diff(other Set[T]) Set[T]
 
+	// DiffLen returns the number of items in this set, but not in the other, without allocating a new set.
+	// It always equals Diff(other).Len().
+	DiffLen(other Set[T]) int
+
 	// SymmetricDiff returns a new set containing all items that are not common to both sets.
 	SymmetricDiff(other Set[T]) Set[T]
 
 	// Equal returns a boolean indicating if both sets are equal.
 	// That is, both have the same number of elements and the same elements.
+	// Any implementation of Set[T] may be passed as other: when it is not the same concrete type
+	// as this set, every element of this set is checked with other.Contains. Equal never panics.
+	// Two resolving sets are equal when they hold the same keys and the same element under every key. Elements of
+	// priority sets are the same when neither is less than the other; other elements are compared with
+	// reflect.DeepEqual, since a resolver such as KeepLast does not order them. Equal is symmetric.
 	Equal(other Set[T]) bool
 
-	// Intersect returns a new set containing only elements that exist in both sets
+	// Intersect returns a new set containing only elements that exist in both sets.
+	// It iterates the smaller of the two sets and probes the larger one, even when they are different implementations.
 	Intersect(other Set[T]) Set[T]
 
+	// IntersectLen returns the number of elements that exist in both sets, without allocating a new set.
+	// It always equals Intersect(other).Len().
+	IntersectLen(other Set[T]) int
+
+	// Disjoint returns a boolean indicating if the set and other have no element in common. It iterates the
+	// smaller set and stops at the first shared element, without building the intersection.
+	Disjoint(other Set[T]) bool
+
+	// IntersectWith removes from this set, in place, every element that is not in the other set.
+	// Unlike Intersect, no new set is allocated.
+	IntersectWith(other Set[T])
+
 	// IsSubset returns a boolean indicating if all elements in this set are also in the other set.
 	IsSubset(other Set[T]) bool
 
@@ -54,25 +149,141 @@ type Set[T any] interface {
 	// but the sets are not equal
 	IsProperSuperset(other Set[T]) bool
 
-	// Iter returns a channel of all the elements in the set which allows the caller to range over the elements
+	// Iter returns a channel of all the elements in the set which allows the caller to range over the elements.
+	// The elements are copied into the channel before it is returned, so no goroutine or lock outlives the call:
+	// the caller may stop ranging at any time and may mutate the set while ranging.
+	// That copy makes memory use grow with the size of the set; prefer IterContext or Iterator for very large sets.
 	Iter() <-chan T
 
-	// Pop removes and returns an arbitrary item from the set
+	// IterContext returns a channel of all the elements in the set, fed by a goroutine that stops
+	// and closes the channel once ctx is done. Thread-safe sets hold their read lock until the goroutine stops,
+	// so callers that stop ranging early must cancel ctx, and must not mutate the set while ranging.
+	// The channel buffers at most 1024 elements, however large the set, and the goroutine blocks until they are received.
+	IterContext(ctx context.Context) <-chan T
+
+	// Iterator returns an iterator over all the elements in the set for use with range-over-func.
+	// It involves no goroutine and stops as soon as the loop body breaks.
+	// Thread-safe sets hold their read lock for the duration of the loop, so the set must not be
+	// mutated from inside the loop.
+	Iterator() iter.Seq[T]
+
+	// Consume removes all elements from the set and returns a channel that yields each of them once,
+	// so the set is empty once the channel is drained. Elements are removed up front, so any that are
+	// not received are dropped. Mutating the set while consuming it is undefined.
+	Consume() <-chan T
+
+	// Pop removes and returns an arbitrary item from the set.
+	// Priority sets instead remove their greatest element, so that repeated calls drain the set in priority order.
+	// Finding it takes a pass over the whole set.
 	Pop() (T, bool)
 
+	// PopN removes and returns up to n items from the set, in the order Pop would remove them.
+	// It returns an empty slice if the set is empty or n is not positive.
+	PopN(n int) []T
+
+	// Sample returns a uniformly random element without removing it, and false if the set is empty.
+	Sample() (T, bool)
+
+	// SampleN returns up to n distinct elements chosen uniformly at random without removing them, in no
+	// particular order. It returns an empty slice if the set is empty or n is not positive.
+	SampleN(n int) []T
+
 	// Remove removes the given item from the set
 	Remove(v ...T)
 
+	// RemoveSet removes every element of other from this set in place. Unlike Diff, no new set is allocated.
+	// Resolving sets remove elements by key.
+	RemoveSet(other Set[T])
+
+	// RemoveIf removes every element for which pred returns true in a single pass and returns how many were removed.
+	// Thread-safe sets hold their write lock for the whole pass, so pred must not access the set.
+	RemoveIf(pred func(T) bool) int
+
+	// ReplaceAll replaces the contents of the set with the given items.
+	// Thread-safe sets do so in a single critical section, so readers never see a partially replaced set,
+	// unlike Clear followed by Add.
+	ReplaceAll(items []T)
+
+	// Toggle flips the membership of each given item: items in the set are removed and items not in the set are added.
+	// It returns the net change in the number of elements in the set.
+	Toggle(v ...T) int
+
 	// Union returns a new set containing all elements from both sets
 	Union(other Set[T]) Set[T]
 
+	// UnionWith adds every element of the other set to this set in place and returns whether the set changed.
+	// It is the in-place counterpart of Union and behaves exactly like AddSet.
+	UnionWith(other Set[T]) bool
+
 	// ToSlice returns a slice containing all elements in the set
 	ToSlice() []T
 
+	// ToSortedSlice returns a slice containing all elements in the set, sorted by less.
+	ToSortedSlice(less func(a, b T) bool) []T
+
+	// AppendTo appends all elements in the set to dst and returns the extended slice.
+	// The order of the appended elements is unspecified.
+	AppendTo(dst []T) []T
+
 	// String returns a string representation of the set
 	String() string
 }
 
+// ReadOnlySet represents the read operations of a set. Every Set is also a ReadOnlySet.
+type ReadOnlySet[T any] interface {
+	// Len returns the number of elements in the set
+	Len() int
+
+	// Contains returns a boolean indicating if all the given items are in the set
+	Contains(v ...T) bool
+
+	// Each iterates over items in the set applying the given function on each element.
+	// Breaks iteration if the given function returns false
+	Each(fn func(T) bool)
+
+	// Iter returns a channel of all the elements in the set which allows the caller to range over the elements.
+	// The elements are copied into the channel before it is returned, so the caller may stop ranging at any time.
+	Iter() <-chan T
+
+	// ToSlice returns a slice containing all elements in the set
+	ToSlice() []T
+}
+
+// ResolvingSet represents a set whose elements are unique by a key and whose conflicts are settled by a Resolver.
+type ResolvingSet[T any, U comparable] interface {
+	Set[T]
+
+	// Update stores the given item under its key, replacing any item already stored under that key.
+	// Unlike Add, which only replaces an item when the resolver says so, Update always replaces.
+	// It returns a boolean indicating if an item was previously stored under the key.
+	Update(v T) bool
+
+	// Upsert adds the given item like Add, letting the resolver settle a conflict with the item already stored under
+	// its key, and returns the item now stored under that key along with whether it replaced an existing item.
+	// A new key is stored without replacing anything, and a resolver keeping the existing item returns that item.
+	Upsert(v T) (stored T, replaced bool)
+
+	// RemoveReturning removes the items stored under the keys of the given items and returns the removed items,
+	// which may differ from the given ones. Keys that are not in the set are skipped.
+	RemoveReturning(v ...T) []T
+
+	// Get returns the item stored under the given key, and false if no item is stored under it.
+	Get(key U) (T, bool)
+
+	// RemoveKey removes the items stored under the given keys. Keys that are not in the set are skipped.
+	RemoveKey(keys ...U)
+
+	// CloneTyped returns a copy of the set like Clone, without losing the ResolvingSet API.
+	CloneTyped() ResolvingSet[T, U]
+
+	// KeyFunc returns the KeyGetter the set uses to compute the key of each element.
+	KeyFunc() KeyGetter[T, U]
+
+	// ResolverFunc returns the Resolver the set uses to settle conflicting keys, which may be nil.
+	// Together with KeyFunc it allows building a set that shares the keying of this one with a different resolver.
+	ResolverFunc() Resolver[T]
+}
+
 func NewSet[T comparable](v ...T) Set[T] {
 	set := newSafeSimpleSet[T]()
 	set.Add(v...)
@@ -85,10 +296,338 @@ func NewThreadUnsafeSet[T comparable](v ...T) Set[T] {
 	return set
 }
 
-func NewResolvingSet[T any, U comparable](keyGetter KeyGetter[T, U], resolver Resolver[T]) Set[T] {
+// NewAdaptiveSet returns a thread-safe set that is backed by a slice while it holds few elements
+// and migrates to a map once it holds more than 16, moving back to a slice when it drops below 8.
+// Each migration copies every element.
+func NewAdaptiveSet[T comparable](v ...T) Set[T] {
+	set := &safeSet[T]{set: newUnsafeAdaptiveSet[T]()}
+	set.Add(v...)
+	return set
+}
+
+// NewBitSet returns a thread-safe set of integers backed by a bitmap, which suits dense non-negative integers such as
+// IDs in 0..n far better than a map. maxHint is the largest value expected; the bitmap grows as needed beyond it, so
+// its memory use is proportional to its largest element rather than to Len. Negative values, and values so large that
+// the bitmap would grow out of proportion to Len, are kept in a map instead and cost as much as in NewSet.
+// Elements are visited in ascending order and Pop removes the smallest.
+func NewBitSet(maxHint int, v ...int) Set[int] {
+	set := &safeSet[int]{set: newUnsafeBitSet(maxHint)}
+	set.Add(v...)
+	return set
+}
+
+// NewThreadUnsafeBitSet is the thread unsafe variant of NewBitSet.
+func NewThreadUnsafeBitSet(maxHint int, v ...int) Set[int] {
+	set := newUnsafeBitSet(maxHint)
+	set.Add(v...)
+	return set
+}
+
+// NewOrderedSet returns a thread-safe set that remembers the order in which its elements were added. Each, Iter,
+// ToSlice and String visit elements in that order, re-adding an element that is already present doesn't move it, and
+// Pop removes the earliest added element.
+func NewOrderedSet[T comparable](v ...T) Set[T] {
+	set := &safeSet[T]{set: newUnsafeOrderedSet[T]()}
+	set.Add(v...)
+	return set
+}
+
+// NewThreadUnsafeOrderedSet is the thread unsafe variant of NewOrderedSet.
+func NewThreadUnsafeOrderedSet[T comparable](v ...T) Set[T] {
+	set := newUnsafeOrderedSet[T]()
+	set.Add(v...)
+	return set
+}
+
+// NewSortedSet returns a thread-safe set that keeps its elements sorted by less, so Each, Iter, ToSlice and String
+// visit them in ascending order and Pop removes the smallest. Unlike a ResolvingSet, which deduplicates by key, less
+// also defines equality: two elements are equal when neither is less than the other, and adding an element equal to
+// one already present keeps the existing element. less must be a strict weak ordering.
+func NewSortedSet[T any](less func(a, b T) bool, v ...T) Set[T] {
+	set := &safeSet[T]{set: newUnsafeSortedSet(less)}
+	set.Add(v...)
+	return set
+}
+
+// NewThreadUnsafeSortedSet is the thread unsafe variant of NewSortedSet.
+func NewThreadUnsafeSortedSet[T any](less func(a, b T) bool, v ...T) Set[T] {
+	set := newUnsafeSortedSet(less)
+	set.Add(v...)
+	return set
+}
+
+// NewResolvingSet returns a thread-safe set whose elements are unique by the key keyGetter returns for them.
+// When an element is added under a key that is already in the set, resolver decides which of the two is kept.
+// A nil resolver keeps the first element added for each key, so later elements with the same key are dropped;
+// use Update to replace an element regardless of the resolver.
+func NewResolvingSet[T any, U comparable](keyGetter KeyGetter[T, U], resolver Resolver[T]) ResolvingSet[T, U] {
 	return newSafeResolvingSet(keyGetter, resolver)
 }
 
-func NewThreadUnsafeResolvingSet[T any, U comparable](keyGetter KeyGetter[T, U], resolver Resolver[T]) Set[T] {
+// NewThreadUnsafeResolvingSet is the thread unsafe variant of NewResolvingSet. A nil resolver likewise keeps the
+// first element added for each key.
+func NewThreadUnsafeResolvingSet[T any, U comparable](keyGetter KeyGetter[T, U], resolver Resolver[T]) ResolvingSet[T, U] {
 	return newUnsafeResolvingSet(keyGetter, resolver)
 }
+
+// NewPrioritySet returns a thread-safe resolving set that keeps the greater of two elements with the same key
+// according to less, as MaxResolver does, and whose Pop and PopN remove the greatest elements first.
+// A set built by NewResolvingSet pops arbitrary elements whatever its resolver, since a resolver such as KeepLast or
+// MergeResolver does not order elements. less must be a strict weak ordering.
+func NewPrioritySet[T any, U comparable](keyGetter KeyGetter[T, U], less func(a, b T) bool) ResolvingSet[T, U] {
+	return wrapResolving(newUnsafePrioritySet(keyGetter, less))
+}
+
+// NewThreadUnsafePrioritySet is the thread unsafe variant of NewPrioritySet.
+func NewThreadUnsafePrioritySet[T any, U comparable](keyGetter KeyGetter[T, U], less func(a, b T) bool) ResolvingSet[T, U] {
+	return newUnsafePrioritySet(keyGetter, less)
+}
+
+// NewSetWithCapacity returns a thread-safe set whose backing map is sized for capacity elements up front,
+// which avoids rehashing while a large set is populated. The capacity is only a hint and doesn't affect Len.
+func NewSetWithCapacity[T comparable](capacity int, v ...T) Set[T] {
+	set := &safeSet[T]{set: newUnsafeSimpleSetWithCapacity[T](capacity)}
+	set.Add(v...)
+	return set
+}
+
+// NewThreadUnsafeSetWithCapacity is the thread unsafe variant of NewSetWithCapacity.
+func NewThreadUnsafeSetWithCapacity[T comparable](capacity int, v ...T) Set[T] {
+	set := newUnsafeSimpleSetWithCapacity[T](capacity)
+	set.Add(v...)
+	return set
+}
+
+// NewSetFromMapKeys returns a thread-safe set of the keys of m. The set doesn't share memory with m,
+// so later changes to m are not reflected in the set.
+func NewSetFromMapKeys[K comparable, V any](m map[K]V) Set[K] {
+	return &safeSet[K]{set: newUnsafeSetFromMapKeys(m)}
+}
+
+// NewThreadUnsafeSetFromMapKeys is the thread unsafe variant of NewSetFromMapKeys.
+func NewThreadUnsafeSetFromMapKeys[K comparable, V any](m map[K]V) Set[K] {
+	return newUnsafeSetFromMapKeys(m)
+}
+
+// NewSetFromChannel returns a thread-safe set of the distinct values received from ch.
+// It blocks until ch is closed, so it never returns for a channel that is never closed.
+func NewSetFromChannel[T comparable](ch <-chan T) Set[T] {
+	set := newUnsafeSimpleSet[T]()
+	for v := range ch {
+		set.add(v)
+	}
+	return &safeSet[T]{set: set}
+}
+
+func newUnsafeSetFromMapKeys[K comparable, V any](m map[K]V) *unsafeSimpleSet[K] {
+	set := newUnsafeSimpleSetWithCapacity[K](len(m))
+	for key := range m {
+		set.add(key)
+	}
+	return set
+}
+
+// NewEquivSet returns a thread-safe set that treats elements as equal when they share the same canonical form,
+// as returned by canonical. It is a lighter alternative to a resolving set for comparable elements:
+// the set stores the canonical form of every element added, so equivalent elements collapse into one.
+// canonical must return its argument unchanged when given a canonical form.
+func NewEquivSet[T comparable](canonical func(T) T, v ...T) Set[T] {
+	// a cloning set keyed by the canonical form that stores the canonical form in place of each element
+	set := NewCloningSet(canonical, canonical)
+	set.Add(v...)
+	return set
+}
+
+// FromSlice returns a thread-safe resolving set of items, deduplicated by the key returned by keyGetter.
+// When several items share a key, the last one in items is kept, as are later items added with that key.
+func FromSlice[T any, U comparable](items []T, keyGetter KeyGetter[T, U]) Set[T] {
+	set := newSafeResolvingSet(keyGetter, KeepLast[T]())
+	set.Add(items...)
+	return set
+}
+
+// equalByContains compares two sets of any implementation using only the Set interface.
+func equalByContains[T any](s, other Set[T]) bool {
+	if s.Len() != other.Len() {
+		return false
+	}
+	equal := true
+	s.Each(func(elem T) bool {
+		equal = other.Contains(elem)
+		return equal
+	})
+	return equal
+}
+
+// diffByContains adds to dst every element of s that is not in other, using only the Set interface.
+func diffByContains[T any](dst, s, other Set[T]) Set[T] {
+	s.Each(func(elem T) bool {
+		if !other.Contains(elem) {
+			dst.Add(elem)
+		}
+		return true
+	})
+	return dst
+}
+
+// symmetricDiffByContains adds to dst every element that is in exactly one of s and other, using only the Set interface.
+func symmetricDiffByContains[T any](dst, s, other Set[T]) Set[T] {
+	diffByContains(dst, s, other)
+	return diffByContains(dst, other, s)
+}
+
+// diffLenByContains returns the number of elements of s that are not in other, using only the Set interface.
+func diffLenByContains[T any](s, other Set[T]) int {
+	count := 0
+	s.Each(func(elem T) bool {
+		if !other.Contains(elem) {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// intersectLenByContains returns the number of elements that are in both s and other, using only the Set interface.
+// Like intersectByContains, it iterates the smaller set and probes the larger one.
+func intersectLenByContains[T any](s, other Set[T]) int {
+	smallerSet, largerSet := s, other
+	if other.Len() < s.Len() {
+		smallerSet, largerSet = other, s
+	}
+	count := 0
+	smallerSet.Each(func(elem T) bool {
+		if largerSet.Contains(elem) {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// disjointByContains reports whether no element is in both s and other, using only the Set interface.
+// It iterates the smaller set, stopping at the first element the larger one contains.
+func disjointByContains[T any](s, other Set[T]) bool {
+	smallerSet, largerSet := s, other
+	if other.Len() < s.Len() {
+		smallerSet, largerSet = other, s
+	}
+	disjoint := true
+	smallerSet.Each(func(elem T) bool {
+		disjoint = !largerSet.Contains(elem)
+		return disjoint
+	})
+	return disjoint
+}
+
+// intersectByContains adds to dst every element that is in both s and other, using only the Set interface.
+// It iterates the smaller set and probes the larger one.
+func intersectByContains[T any](dst, s, other Set[T]) Set[T] {
+	smallerSet, largerSet := s, other
+	if other.Len() < s.Len() {
+		smallerSet, largerSet = other, s
+	}
+	smallerSet.Each(func(elem T) bool {
+		if largerSet.Contains(elem) {
+			dst.Add(elem)
+		}
+		return true
+	})
+	return dst
+}
+
+// unionByContains adds to dst every element of s and other, using only the Set interface.
+func unionByContains[T any](dst, s, other Set[T]) Set[T] {
+	s.Each(func(elem T) bool {
+		dst.Add(elem)
+		return true
+	})
+	other.Each(func(elem T) bool {
+		dst.Add(elem)
+		return true
+	})
+	return dst
+}
+
+// isSubsetByContains reports whether every element of s is in other, using only the Set interface.
+func isSubsetByContains[T any](s, other Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	subset := true
+	s.Each(func(elem T) bool {
+		subset = other.Contains(elem)
+		return subset
+	})
+	return subset
+}
+
+// removeIfByEachMutable removes from s every element for which pred returns true, using only the Set interface.
+func removeIfByEachMutable[T any](s Set[T], pred func(T) bool) int {
+	prevLen := s.Len()
+	s.EachMutable(func(elem T) bool {
+		return !pred(elem)
+	})
+	return prevLen - s.Len()
+}
+
+// iterBufferSize caps the channel buffer of IterContext, so its memory use does not grow with the size of the set.
+const iterBufferSize = 1024
+
+// iterContext returns a channel fed with the elements visited by each from a goroutine that stops once ctx is done.
+// The channel buffers up to size elements, capped at iterBufferSize.
+func iterContext[T any](ctx context.Context, size int, each func(fn func(T) bool)) <-chan T {
+	ch := make(chan T, min(size, iterBufferSize))
+	go func() {
+		defer close(ch)
+		each(func(elem T) bool {
+			select {
+			case ch <- elem:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return ch
+}
+
+// sliceChan returns a closed channel buffered with the given elements.
+func sliceChan[T any](elems []T) <-chan T {
+	ch := make(chan T, len(elems))
+	for _, elem := range elems {
+		ch <- elem
+	}
+	close(ch)
+	return ch
+}
+
+// anyOf returns a boolean indicating if pred returns true for any element visited by each.
+func anyOf[T any](each func(fn func(T) bool), pred func(T) bool) bool {
+	found := false
+	each(func(elem T) bool {
+		found = pred(elem)
+		return !found
+	})
+	return found
+}
+
+// allOf returns a boolean indicating if pred returns true for every element visited by each.
+func allOf[T any](each func(fn func(T) bool), pred func(T) bool) bool {
+	return !anyOf(each, func(elem T) bool { return !pred(elem) })
+}
+
+// sortedSlice sorts elems by less and returns it.
+func sortedSlice[T any](elems []T, less func(a, b T) bool) []T {
+	sort.Slice(elems, func(i, j int) bool { return less(elems[i], elems[j]) })
+	return elems
+}
+
+// eachOf applies fn on each of elems, breaking iteration if it returns false.
+func eachOf[T any](elems []T, fn func(T) bool) {
+	for _, elem := range elems {
+		if !fn(elem) {
+			return
+		}
+	}
+}
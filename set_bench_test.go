@@ -0,0 +1,261 @@
+package goset_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sfodje/goset"
+)
+
+func newBenchSet(n int) goset.Set[int] {
+	set := goset.NewThreadUnsafeSet[int]()
+	for i := 0; i < n; i++ {
+		set.Add(i)
+	}
+	return set
+}
+
+// BenchmarkEmptyOther compares binary operations against an empty set, which take a fast path,
+// with the same operations against a single-element set, which check membership per element.
+func BenchmarkEmptyOther(b *testing.B) {
+	set := newBenchSet(100_000)
+	empty := goset.NewThreadUnsafeSet[int]()
+	single := goset.NewThreadUnsafeSet(-1)
+
+	ops := []struct {
+		name string
+		fn   func(other goset.Set[int]) goset.Set[int]
+	}{
+		{name: "Diff", fn: set.Diff},
+		{name: "Intersect", fn: set.Intersect},
+		{name: "Union", fn: set.Union},
+	}
+	for _, op := range ops {
+		b.Run(op.name+"/Empty", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				op.fn(empty)
+			}
+		})
+		b.Run(op.name+"/Single", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				op.fn(single)
+			}
+		})
+	}
+}
+
+// BenchmarkContainsMostlyMisses queries a large set for absent elements, where a Bloom prefilter
+// answers without taking the set's lock.
+func BenchmarkContainsMostlyMisses(b *testing.B) {
+	const n = 1_000_000
+	sets := []struct {
+		name string
+		set  goset.Set[int]
+	}{
+		{name: "NoPrefilter", set: goset.NewSet[int]()},
+		{name: "BloomPrefilter", set: goset.NewSetWithOptions[int](goset.WithBloomPrefilter(n, 0.01))},
+	}
+	for _, bs := range sets {
+		for i := 0; i < n; i++ {
+			bs.set.Add(i)
+		}
+		b.Run(bs.name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := n
+				for pb.Next() {
+					bs.set.Contains(i)
+					i++
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkAdaptiveSet compares adding and looking up elements in an adaptive set
+// against a map-backed set at sizes on both sides of the migration threshold.
+func BenchmarkAdaptiveSet(b *testing.B) {
+	factories := []struct {
+		name   string
+		newSet func() goset.Set[int]
+	}{
+		{name: "Map", newSet: func() goset.Set[int] { return goset.NewSet[int]() }},
+		{name: "Adaptive", newSet: func() goset.Set[int] { return goset.NewAdaptiveSet[int]() }},
+	}
+	for _, size := range []int{4, 8, 16, 64, 1024} {
+		for _, f := range factories {
+			b.Run(fmt.Sprintf("%s/%d", f.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					set := f.newSet()
+					for j := 0; j < size; j++ {
+						set.Add(j)
+					}
+					for j := 0; j < size; j++ {
+						set.Contains(j)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkPopulate compares populating a set with and without a capacity hint.
+func BenchmarkPopulate(b *testing.B) {
+	const n = 1_000_000
+	constructors := []struct {
+		name   string
+		newSet func() goset.Set[int]
+	}{
+		{name: "NewSet", newSet: func() goset.Set[int] { return goset.NewSet[int]() }},
+		{name: "NewSetWithCapacity", newSet: func() goset.Set[int] { return goset.NewSetWithCapacity[int](n) }},
+	}
+	for _, c := range constructors {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				set := c.newSet()
+				for j := 0; j < n; j++ {
+					set.Add(j)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSetBuilder compares building a set with a presized SetBuilder against adding to a new set.
+func BenchmarkSetBuilder(b *testing.B) {
+	const n = 100_000
+	b.Run("NewSet", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			set := goset.NewSet[int]()
+			for j := 0; j < n; j++ {
+				set.Add(j)
+			}
+		}
+	})
+	b.Run("SetBuilder", func(b *testing.B) {
+		b.ReportAllocs()
+		builder := goset.NewSetBuilder[int](n)
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < n; j++ {
+				builder.Add(j)
+			}
+			builder.Build()
+		}
+	})
+}
+
+// BenchmarkDiffLen compares counting a difference or intersection with DiffLen and IntersectLen
+// against materializing it with Diff or Intersect first.
+func BenchmarkDiffLen(b *testing.B) {
+	setA := newBenchSet(100_000)
+	setB := goset.NewThreadUnsafeSet[int]()
+	for i := 50_000; i < 150_000; i++ {
+		setB.Add(i)
+	}
+
+	ops := []struct {
+		name string
+		fn   func() int
+	}{
+		{name: "Diff", fn: func() int { return setA.Diff(setB).Len() }},
+		{name: "DiffLen", fn: func() int { return setA.DiffLen(setB) }},
+		{name: "Intersect", fn: func() int { return setA.Intersect(setB).Len() }},
+		{name: "IntersectLen", fn: func() int { return setA.IntersectLen(setB) }},
+	}
+	for _, op := range ops {
+		b.Run(op.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				op.fn()
+			}
+		})
+	}
+}
+
+// BenchmarkDisjoint compares Disjoint with building the intersection of two large overlapping sets.
+// Disjoint stops at the first shared element, so it doesn't grow with the size of the sets.
+func BenchmarkDisjoint(b *testing.B) {
+	setA := newBenchSet(100_000)
+	setB := goset.NewThreadUnsafeSet[int]()
+	for i := 50_000; i < 150_000; i++ {
+		setB.Add(i)
+	}
+
+	ops := []struct {
+		name string
+		fn   func() bool
+	}{
+		{name: "Intersect", fn: func() bool { return setA.Intersect(setB).Len() == 0 }},
+		{name: "Disjoint", fn: func() bool { return setA.Disjoint(setB) }},
+	}
+	for _, op := range ops {
+		b.Run(op.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				op.fn()
+			}
+		})
+	}
+}
+
+// BenchmarkIntersectAcrossImplementations intersects a tiny set with huge sets of a different implementation,
+// in both directions. The run time should depend on the size of the tiny set only.
+func BenchmarkIntersectAcrossImplementations(b *testing.B) {
+	tiny := goset.NewAdaptiveSet(1, 2, 3, -1)
+	for _, n := range []int{1_000, 100_000, 1_000_000} {
+		huge := newBenchSet(n)
+		b.Run(fmt.Sprintf("TinyWithHuge/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tiny.Intersect(huge)
+			}
+		})
+		b.Run(fmt.Sprintf("HugeWithTiny/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				huge.Intersect(tiny)
+			}
+		})
+	}
+}
+
+// BenchmarkBitSet compares a bit set with the map-based set for a dense population of 0..1e6.
+func BenchmarkBitSet(b *testing.B) {
+	const n = 1_000_000
+	constructors := []struct {
+		name   string
+		newSet func() goset.Set[int]
+	}{
+		{name: "MapSet", newSet: func() goset.Set[int] { return goset.NewThreadUnsafeSet[int]() }},
+		{name: "BitSet", newSet: func() goset.Set[int] { return goset.NewThreadUnsafeBitSet(n) }},
+	}
+	for _, c := range constructors {
+		b.Run(c.name+"/Populate", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				set := c.newSet()
+				for j := 0; j < n; j++ {
+					set.Add(j)
+				}
+			}
+		})
+
+		setA, setB := c.newSet(), c.newSet()
+		for j := 0; j < n; j++ {
+			setA.Add(j)
+			if j%2 == 0 {
+				setB.Add(j)
+			}
+		}
+		b.Run(c.name+"/Contains", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				setA.Contains(i % n)
+			}
+		})
+		b.Run(c.name+"/Intersect", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				setA.Intersect(setB)
+			}
+		})
+	}
+}
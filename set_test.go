@@ -1,9 +1,15 @@
 package goset_test
 
 import (
+	"context"
+	"fmt"
 	"regexp"
+	"runtime"
 	"sort"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -28,6 +34,34 @@ func TestSets(t *testing.T) {
 			name:   "SafeSimpleSet",
 			newSet: func(v ...int) goset.Set[int] { return goset.NewSet[int](v...) },
 		},
+		{
+			name:   "AdaptiveSet",
+			newSet: func(v ...int) goset.Set[int] { return goset.NewAdaptiveSet[int](v...) },
+		},
+		{
+			name:   "UnsafeBitSet",
+			newSet: func(v ...int) goset.Set[int] { return goset.NewThreadUnsafeBitSet(0, v...) },
+		},
+		{
+			name:   "SafeBitSet",
+			newSet: func(v ...int) goset.Set[int] { return goset.NewBitSet(64, v...) },
+		},
+		{
+			name:   "UnsafeOrderedSet",
+			newSet: func(v ...int) goset.Set[int] { return goset.NewThreadUnsafeOrderedSet(v...) },
+		},
+		{
+			name:   "SafeOrderedSet",
+			newSet: func(v ...int) goset.Set[int] { return goset.NewOrderedSet(v...) },
+		},
+		{
+			name:   "UnsafeSortedSet",
+			newSet: func(v ...int) goset.Set[int] { return goset.NewThreadUnsafeSortedSet(intLess, v...) },
+		},
+		{
+			name:   "SafeSortedSet",
+			newSet: func(v ...int) goset.Set[int] { return goset.NewSortedSet(intLess, v...) },
+		},
 	}
 
 	for _, tc := range testCases {
@@ -42,6 +76,30 @@ func TestSets(t *testing.T) {
 				assert.EqualValues(t, expectedItems, actualItems)
 			})
 
+			t.Run("AddSet", func(t *testing.T) {
+				set := tc.newSet(1, 2)
+
+				assert.True(t, set.AddSet(tc.newSet(2, 3)))
+				assert.True(t, set.AddSet(goset.NewThreadUnsafeSet(4)))
+				assert.True(t, set.AddSet(goset.NewAdaptiveSet(5)))
+				assert.False(t, set.AddSet(goset.NewSet(1, 5)))
+				assert.False(t, set.AddSet(goset.NewSet[int]()))
+				assert.False(t, set.AddSet(set))
+
+				actualItems := set.ToSlice()
+				sort.Ints(actualItems)
+				assert.EqualValues(t, []int{1, 2, 3, 4, 5}, actualItems)
+			})
+
+			t.Run("WouldAdd", func(t *testing.T) {
+				set := tc.newSet(1, 2)
+
+				assert.True(t, set.WouldAdd(3))
+				assert.False(t, set.WouldAdd(1))
+				assert.Equal(t, 2, set.Len())
+				assert.False(t, set.Contains(3))
+			})
+
 			t.Run("Len", func(t *testing.T) {
 				set := tc.newSet(1, 2, 3, 4)
 				assert.Equal(t, 4, set.Len())
@@ -51,6 +109,26 @@ func TestSets(t *testing.T) {
 				assert.Len(t, set.ToSlice(), set.Len())
 			})
 
+			t.Run("IsEmpty/Stats", func(t *testing.T) {
+				set := tc.newSet()
+				assert.True(t, set.IsEmpty())
+				stats := set.Stats()
+				assert.Zero(t, stats.Len)
+				assert.True(t, stats.IsEmpty)
+
+				set.Add(1, 2, 3)
+				assert.False(t, set.IsEmpty())
+				stats = set.Stats()
+				assert.Equal(t, 3, stats.Len)
+				assert.False(t, stats.IsEmpty)
+				if !stats.MapBacked {
+					assert.GreaterOrEqual(t, stats.Capacity, stats.Len)
+				}
+
+				set.Remove(1, 2, 3)
+				assert.True(t, set.IsEmpty())
+			})
+
 			t.Run("Clear", func(t *testing.T) {
 				set := tc.newSet(1, 2, 3, 4, 5, 6)
 				assert.Equal(t, 6, set.Len())
@@ -69,6 +147,18 @@ func TestSets(t *testing.T) {
 				}
 			})
 
+			t.Run("CloneWith", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3)
+				clone := set.CloneWith(func(v int) int { return v * 10 })
+
+				actualItems := clone.ToSlice()
+				sort.Ints(actualItems)
+				assert.EqualValues(t, []int{10, 20, 30}, actualItems)
+				clone.Add(4)
+				assert.Equal(t, 3, set.Len())
+				assert.True(t, tc.newSet(1, 2, 3).Equal(set))
+			})
+
 			t.Run("Contains", func(t *testing.T) {
 				set := tc.newSet(4, 5, 6, 7, 8)
 				assert.True(t, set.Contains(4, 5, 6))
@@ -77,6 +167,15 @@ func TestSets(t *testing.T) {
 				assert.False(t, set.Contains(13))
 			})
 
+			t.Run("ContainsAny", func(t *testing.T) {
+				set := tc.newSet(4, 5, 6)
+				assert.True(t, set.ContainsAny(1, 2, 5))
+				assert.True(t, set.ContainsAny(4, 5, 6))
+				assert.False(t, set.ContainsAny(1, 2, 3))
+				assert.False(t, set.ContainsAny())
+				assert.False(t, tc.newSet().ContainsAny(4))
+			})
+
 			t.Run("Each", func(t *testing.T) {
 				set := tc.newSet(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
 
@@ -103,6 +202,150 @@ func TestSets(t *testing.T) {
 				assert.Len(t, items, 3)
 			})
 
+			t.Run("EachMutable", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+
+				visited := 0
+				set.EachMutable(func(v int) bool {
+					visited++
+					return v%2 == 0
+				})
+				assert.Equal(t, 10, visited)
+				assert.Equal(t, 5, set.Len())
+
+				actualItems := set.ToSlice()
+				sort.Ints(actualItems)
+				assert.EqualValues(t, []int{2, 4, 6, 8, 10}, actualItems)
+			})
+
+			t.Run("EachSnapshot", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3)
+
+				var visited []int
+				set.EachSnapshot(func(v int) bool {
+					visited = append(visited, v)
+					set.Add(v * 10)
+					set.Remove(v)
+					return true
+				})
+				sort.Ints(visited)
+				assert.EqualValues(t, []int{1, 2, 3}, visited)
+				assert.True(t, tc.newSet(10, 20, 30).Equal(set))
+
+				calls := 0
+				set.EachSnapshot(func(int) bool {
+					calls++
+					return false
+				})
+				assert.Equal(t, 1, calls)
+			})
+
+			t.Run("Filter", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3, 4, 5)
+
+				evens := set.Filter(func(v int) bool { return v%2 == 0 })
+				actualItems := evens.ToSlice()
+				sort.Ints(actualItems)
+				assert.EqualValues(t, []int{2, 4}, actualItems)
+
+				evens.Add(6)
+				set.Remove(2)
+				assert.True(t, evens.Contains(2, 6))
+				assert.False(t, set.Contains(6))
+
+				assert.Zero(t, set.Filter(func(int) bool { return false }).Len())
+			})
+
+			t.Run("Partition", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3, 4, 5)
+
+				evens, odds := set.Partition(func(v int) bool { return v%2 == 0 })
+				evenItems, oddItems := evens.ToSlice(), odds.ToSlice()
+				sort.Ints(evenItems)
+				sort.Ints(oddItems)
+				assert.EqualValues(t, []int{2, 4}, evenItems)
+				assert.EqualValues(t, []int{1, 3, 5}, oddItems)
+				assert.Zero(t, evens.IntersectLen(odds))
+				assert.True(t, evens.Union(odds).Equal(set))
+
+				evens.Add(6)
+				assert.False(t, set.Contains(6))
+
+				matching, rest := tc.newSet().Partition(func(int) bool { return true })
+				assert.Zero(t, matching.Len())
+				assert.Zero(t, rest.Len())
+			})
+
+			t.Run("Sample", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3, 4, 5)
+
+				v, ok := set.Sample()
+				assert.True(t, ok)
+				assert.True(t, set.Contains(v))
+				assert.Equal(t, 5, set.Len())
+
+				sample := set.SampleN(3)
+				assert.Len(t, sample, 3)
+				assert.Equal(t, 3, goset.NewThreadUnsafeSet(sample...).Len())
+				assert.True(t, set.Contains(sample...))
+				assert.Len(t, set.SampleN(10), 5)
+				assert.Empty(t, set.SampleN(0))
+				assert.Equal(t, 5, set.Len())
+
+				_, ok = tc.newSet().Sample()
+				assert.False(t, ok)
+				assert.Empty(t, tc.newSet().SampleN(2))
+			})
+
+			t.Run("RemoveIf", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3, 4, 5, 6)
+
+				assert.Equal(t, 3, set.RemoveIf(func(v int) bool { return v%2 == 0 }))
+				actualItems := set.ToSlice()
+				sort.Ints(actualItems)
+				assert.EqualValues(t, []int{1, 3, 5}, actualItems)
+				assert.False(t, set.ContainsAny(2, 4, 6))
+
+				assert.Zero(t, set.RemoveIf(func(v int) bool { return v > 10 }))
+				assert.Equal(t, 3, set.RemoveIf(func(int) bool { return true }))
+				assert.Zero(t, set.Len())
+			})
+
+			t.Run("Disjoint", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3)
+
+				assert.True(t, set.Disjoint(tc.newSet(4, 5, 6)))
+				assert.True(t, set.Disjoint(goset.NewThreadUnsafeSet(4, 5, 6, 7, 8)))
+				assert.False(t, set.Disjoint(tc.newSet(3, 4)))
+				assert.False(t, set.Disjoint(goset.NewSet(0, 1)))
+				assert.False(t, set.Disjoint(set))
+				assert.True(t, set.Disjoint(tc.newSet()))
+				assert.True(t, tc.newSet().Disjoint(set))
+				assert.True(t, tc.newSet().Disjoint(tc.newSet()))
+			})
+
+			t.Run("Any/All", func(t *testing.T) {
+				set := tc.newSet(2, 4, 6)
+				isEven := func(v int) bool { return v%2 == 0 }
+				isBig := func(v int) bool { return v > 4 }
+				isNegative := func(v int) bool { return v < 0 }
+
+				assert.True(t, set.Any(isEven))
+				assert.True(t, set.All(isEven))
+				assert.True(t, set.Any(isBig))
+				assert.False(t, set.All(isBig))
+				assert.False(t, set.Any(isNegative))
+				assert.False(t, set.All(isNegative))
+
+				calls := 0
+				set.Any(func(int) bool { calls++; return true })
+				assert.Equal(t, 1, calls)
+
+				empty := tc.newSet()
+				assert.False(t, empty.Any(isEven))
+				assert.True(t, empty.All(isNegative))
+			})
+
 			t.Run("Diff", func(t *testing.T) {
 				setA := tc.newSet(1, 2, 3)
 				setB := tc.newSet(2, 3, 4, 5)
@@ -111,6 +354,10 @@ func TestSets(t *testing.T) {
 				expectedItems := []int{1}
 				actualItems := diff.ToSlice()
 				assert.EqualValues(t, expectedItems, actualItems)
+				assert.Equal(t, 1, setA.DiffLen(setB))
+				assert.Equal(t, 2, setB.DiffLen(setA))
+				assert.Equal(t, 2, setA.IntersectLen(setB))
+				assert.Zero(t, setA.IntersectLen(tc.newSet()))
 			})
 
 			t.Run("SymmetricDiff", func(t *testing.T) {
@@ -153,6 +400,22 @@ func TestSets(t *testing.T) {
 				assert.EqualValues(t, expectedItems, actualItems)
 			})
 
+			t.Run("IntersectWith", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3, 4)
+				set.IntersectWith(goset.NewThreadUnsafeSet(2, 4, 6))
+				actualItems := set.ToSlice()
+				sort.Ints(actualItems)
+				assert.EqualValues(t, []int{2, 4}, actualItems)
+
+				set.IntersectWith(tc.newSet(2, 4))
+				assert.Equal(t, 2, set.Len())
+				set.IntersectWith(set)
+				assert.Equal(t, 2, set.Len())
+
+				set.IntersectWith(goset.NewSet(5, 6))
+				assert.Zero(t, set.Len())
+			})
+
 			t.Run("IsSubset/IsProperSubset/IsSuperset/IsProperSuperset", func(t *testing.T) {
 				setA := tc.newSet(1, 2, 3)
 				setB := tc.newSet(2, 3)
@@ -174,6 +437,67 @@ func TestSets(t *testing.T) {
 				}
 			})
 
+			t.Run("IterContext", func(t *testing.T) {
+				items := []int{1, 2, 3, 4, 5, 6, 7}
+				set := tc.newSet(items...)
+
+				var actualItems []int
+				for item := range set.IterContext(context.Background()) {
+					actualItems = append(actualItems, item)
+				}
+				sort.Ints(actualItems)
+				assert.EqualValues(t, items, actualItems)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				ch := set.IterContext(ctx)
+				for range ch {
+					break
+				}
+				cancel()
+				// the channel is closed once the producer stops
+				for range ch {
+				}
+				assert.True(t, set.Add(8))
+			})
+
+			t.Run("Iterator", func(t *testing.T) {
+				items := []int{1, 2, 3, 4, 5, 6, 7}
+				set := tc.newSet(items...)
+
+				var actualItems []int
+				for item := range set.Iterator() {
+					actualItems = append(actualItems, item)
+				}
+				sort.Ints(actualItems)
+				assert.EqualValues(t, items, actualItems)
+
+				visited := 0
+				for range set.Iterator() {
+					visited++
+					if visited == 3 {
+						break
+					}
+				}
+				assert.Equal(t, 3, visited)
+				assert.True(t, set.Add(8))
+			})
+
+			t.Run("Consume", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3, 4, 5)
+
+				var consumed []int
+				for v := range set.Consume() {
+					consumed = append(consumed, v)
+				}
+				sort.Ints(consumed)
+				assert.EqualValues(t, []int{1, 2, 3, 4, 5}, consumed)
+				assert.Zero(t, set.Len())
+
+				for range set.Consume() {
+					assert.Fail(t, "empty set must not yield elements")
+				}
+			})
+
 			t.Run("Pop", func(t *testing.T) {
 				set := tc.newSet()
 				assert.Zero(t, set.Len())
@@ -189,6 +513,76 @@ func TestSets(t *testing.T) {
 				assert.Equal(t, 3, set.Len())
 			})
 
+			t.Run("RemoveSet", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3, 4, 5)
+
+				set.RemoveSet(tc.newSet(1, 6))
+				assert.Equal(t, 4, set.Len())
+				set.RemoveSet(goset.NewThreadUnsafeSet(2, 3))
+				assert.Equal(t, 2, set.Len())
+				set.RemoveSet(goset.NewSet(7, 8))
+				set.RemoveSet(goset.NewSet[int]())
+
+				actualItems := set.ToSlice()
+				sort.Ints(actualItems)
+				assert.EqualValues(t, []int{4, 5}, actualItems)
+
+				set.RemoveSet(set)
+				assert.Zero(t, set.Len())
+			})
+
+			t.Run("PopN", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3, 4, 5)
+
+				assert.Empty(t, set.PopN(0))
+				assert.Equal(t, 5, set.Len())
+
+				popped := set.PopN(2)
+				assert.Len(t, popped, 2)
+				assert.Equal(t, 3, set.Len())
+				assert.False(t, set.Contains(popped...))
+
+				rest := set.PopN(3)
+				assert.Len(t, rest, 3)
+				assert.Zero(t, set.Len())
+				all := append(popped, rest...)
+				sort.Ints(all)
+				assert.EqualValues(t, []int{1, 2, 3, 4, 5}, all)
+
+				set.Add(6, 7)
+				assert.ElementsMatch(t, []int{6, 7}, set.PopN(10))
+				assert.NotNil(t, set.PopN(1))
+				assert.Empty(t, set.PopN(1))
+			})
+
+			t.Run("ReplaceAll", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3)
+				set.ReplaceAll([]int{3, 4, 5, 5})
+
+				actualItems := set.ToSlice()
+				sort.Ints(actualItems)
+				assert.EqualValues(t, []int{3, 4, 5}, actualItems)
+
+				set.ReplaceAll(nil)
+				assert.Zero(t, set.Len())
+			})
+
+			t.Run("Toggle", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3)
+
+				assert.Equal(t, 1, set.Toggle(2, 4, 5))
+				actualItems := set.ToSlice()
+				sort.Ints(actualItems)
+				assert.EqualValues(t, []int{1, 3, 4, 5}, actualItems)
+
+				assert.Equal(t, -2, set.Toggle(1, 3, 3, 3))
+				actualItems = set.ToSlice()
+				sort.Ints(actualItems)
+				assert.EqualValues(t, []int{4, 5}, actualItems)
+
+				assert.Zero(t, set.Toggle())
+			})
+
 			t.Run("Union", func(t *testing.T) {
 				setA := tc.newSet(1, 3, 5, 7, 9)
 				setB := tc.newSet(2, 4, 6, 8)
@@ -206,6 +600,57 @@ func TestSets(t *testing.T) {
 				assert.EqualValues(t, expectedItems, actualB)
 			})
 
+			t.Run("ToSortedSlice", func(t *testing.T) {
+				set := tc.newSet(3, 1, 4, 5, 9, 2, 6)
+
+				assert.EqualValues(t, []int{1, 2, 3, 4, 5, 6, 9}, set.ToSortedSlice(func(a, b int) bool { return a < b }))
+				assert.EqualValues(t, []int{9, 6, 5, 4, 3, 2, 1}, set.ToSortedSlice(func(a, b int) bool { return a > b }))
+				assert.Empty(t, tc.newSet().ToSortedSlice(func(a, b int) bool { return a < b }))
+			})
+
+			t.Run("UnionWith", func(t *testing.T) {
+				sets := []goset.Set[int]{tc.newSet(1, 2), tc.newSet(2, 3), goset.NewSet(3, 4), goset.NewThreadUnsafeSet(5)}
+
+				folded := tc.newSet()
+				union := tc.newSet()
+				for _, set := range sets {
+					assert.True(t, folded.UnionWith(set))
+					union = union.Union(set)
+				}
+				assert.True(t, union.Equal(folded))
+
+				assert.False(t, folded.UnionWith(sets[1]))
+				assert.False(t, folded.UnionWith(tc.newSet()))
+				assert.Equal(t, 5, folded.Len())
+			})
+
+			t.Run("AppendTo", func(t *testing.T) {
+				set := tc.newSet(3, 4, 5)
+				dst := []int{1, 2}
+
+				actualItems := set.AppendTo(dst)
+				assert.Len(t, actualItems, 5)
+				assert.EqualValues(t, []int{1, 2}, actualItems[:2])
+				sort.Ints(actualItems)
+				assert.EqualValues(t, []int{1, 2, 3, 4, 5}, actualItems)
+			})
+
+			t.Run("EmptyOther", func(t *testing.T) {
+				set := tc.newSet(1, 2, 3)
+				empty := tc.newSet()
+
+				diff := set.Diff(empty)
+				assert.True(t, diff.Equal(set))
+				diff.Add(4)
+				assert.False(t, set.Contains(4))
+
+				assert.Zero(t, set.Intersect(empty).Len())
+
+				actualItems := set.Union(empty).ToSlice()
+				sort.Ints(actualItems)
+				assert.EqualValues(t, []int{1, 2, 3}, actualItems)
+			})
+
 			t.Run("String", func(t *testing.T) {
 				set := tc.newSet(1, 2, 3)
 				assert.Regexp(t, intSetStringRegex, set.String())
@@ -217,34 +662,22 @@ func TestSets(t *testing.T) {
 func TestResolvingSets(t *testing.T) {
 	testCases := []struct {
 		name   string
-		newSet func() goset.Set[*TestType]
+		newSet func() goset.ResolvingSet[*TestType, int]
 	}{
 		{
 			name: "UnsafeResolvingSet",
-			newSet: func() goset.Set[*TestType] {
-				return goset.NewThreadUnsafeResolvingSet(func(item *TestType) int {
+			newSet: func() goset.ResolvingSet[*TestType, int] {
+				return goset.NewThreadUnsafePrioritySet(func(item *TestType) int {
 					return item.ID
-				}, func(foundItem, newItem *TestType) (*TestType, bool) {
-					if newItem.Importance > foundItem.Importance {
-						return newItem, true
-					}
-					return foundItem, false
-				})
+				}, lessImportant)
 			},
 		},
 		{
 			name: "SafeResolvingSet",
-			newSet: func() goset.Set[*TestType] {
-				return goset.NewResolvingSet(
-					func(item *TestType) int {
-						return item.ID
-					},
-					func(foundItem, newItem *TestType) (*TestType, bool) {
-						if newItem.Importance > foundItem.Importance {
-							return newItem, true
-						}
-						return foundItem, false
-					})
+			newSet: func() goset.ResolvingSet[*TestType, int] {
+				return goset.NewPrioritySet(func(item *TestType) int {
+					return item.ID
+				}, lessImportant)
 			},
 		},
 	}
@@ -262,6 +695,21 @@ func TestResolvingSets(t *testing.T) {
 				assert.EqualValues(t, expectedItems, actualItems)
 			})
 
+			t.Run("AddSet", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems[0])
+
+				other := goset.NewThreadUnsafeSet(testItems...)
+				assert.True(t, set.AddSet(other))
+				expectedItems := []*TestType{testItems[5], testItems[3], testItems[2]}
+				actualItems := set.ToSlice()
+				sortTestItems(actualItems)
+				assert.EqualValues(t, expectedItems, actualItems)
+
+				assert.False(t, set.AddSet(other))
+				assert.False(t, set.AddSet(set.Clone()))
+			})
+
 			t.Run("Clear", func(t *testing.T) {
 				set := tc.newSet()
 
@@ -291,77 +739,306 @@ func TestResolvingSets(t *testing.T) {
 				assert.True(t, set.Equal(clone))
 				assert.EqualValues(t, expectedItems, setItems)
 				assert.EqualValues(t, expectedItems, clonedItems)
+
+				// the clone has its own backing map
+				clone.Remove(testItems[5])
+				clone.Add(&TestType{ID: 100, Name: "One Hundred", Importance: 1})
+				setItems = set.ToSlice()
+				sortTestItems(setItems)
+				assert.EqualValues(t, expectedItems, setItems)
+
+				// but pointer elements are shared with the original
+				shared := &TestType{ID: 200, Name: "Two Hundred", Importance: 1}
+				set.Add(shared)
+				sharedClone := set.Clone()
+				for _, item := range sharedClone.ToSlice() {
+					if item.ID == shared.ID {
+						item.Name = "Renamed"
+					}
+				}
+				assert.Equal(t, "Renamed", shared.Name)
 			})
 
-			t.Run("Contains", func(t *testing.T) {
+			t.Run("CloneWith", func(t *testing.T) {
 				set := tc.newSet()
 				set.Add(testItems...)
-				assert.True(t, set.Contains(testItems[5], testItems[3], testItems[2]))
-				assert.True(t, set.Contains(testItems[3]))
-				assert.True(t, set.Contains(testItems...))
-				assert.False(t, set.Contains(&TestType{ID: 100, Name: "One Hundred", Importance: 1}))
+
+				clone := set.CloneWith(cloneTestType)
+				assert.True(t, set.Equal(clone))
+				for _, item := range clone.ToSlice() {
+					item.Name = "Renamed"
+				}
+				for _, item := range set.ToSlice() {
+					assert.NotEqual(t, "Renamed", item.Name)
+				}
+
+				// the resolver still decides conflicts in the clone
+				assert.False(t, clone.Add(testItems[4]))
+				clone.Remove(testItems[5])
+				assert.Equal(t, 3, set.Len())
+				assert.Equal(t, 2, clone.Len())
 			})
 
-			t.Run("Diff/SymmetricDiff", func(t *testing.T) {
-				setA := tc.newSet()
-				setA.Add(testItems...)
+			t.Run("CloneTyped", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems[0], testItems[1])
+
+				for _, clone := range []goset.Set[*TestType]{set.Clone(), set.CloneTyped()} {
+					// the resolver still decides conflicts in the clone
+					assert.True(t, clone.Add(testItems[5]))
+					assert.False(t, clone.Add(testItems[4]))
+					assert.Contains(t, clone.ToSlice(), testItems[5])
+					assert.Contains(t, set.ToSlice(), testItems[0])
+				}
 
-				setB := tc.newSet()
-				setB.Add(testItems[0], testItems[2], testItems[4], &TestType{ID: 100, Name: "One Hundred", Importance: 1})
+				clone := set.CloneTyped()
+				assert.True(t, clone.Update(testItems[4]))
+				assert.Contains(t, clone.ToSlice(), testItems[4])
+				assert.Contains(t, set.ToSlice(), testItems[0])
+			})
 
-				diff := setA.Diff(setB)
-				expectedItems := []*TestType{testItems[3]}
-				actualItems := diff.ToSlice()
-				sortTestItems(actualItems)
-				assert.EqualValues(t, expectedItems, actualItems)
+			t.Run("RemoveIf", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+				set.Add(&TestType{ID: 4, Name: "Four", Importance: 1})
 
-				diff = setB.Diff(setA)
-				expectedItems = []*TestType{{ID: 100, Name: "One Hundred", Importance: 1}}
-				actualItems = diff.ToSlice()
+				removed := set.RemoveIf(func(item *TestType) bool { return item.Importance < 2 })
+				assert.Equal(t, 2, removed)
+				actualItems := set.ToSlice()
 				sortTestItems(actualItems)
-				assert.EqualValues(t, expectedItems, actualItems)
+				assert.EqualValues(t, []*TestType{testItems[5], testItems[3]}, actualItems)
 
-				diff = setA.SymmetricDiff(setB)
-				expectedItems = []*TestType{testItems[3], {ID: 100, Name: "One Hundred", Importance: 1}}
-				actualItems = diff.ToSlice()
-				sortTestItems(actualItems)
-				assert.EqualValues(t, expectedItems, actualItems)
+				// the keys of removed elements are free again
+				assert.True(t, set.Add(testItems[2]))
 			})
 
-			t.Run("Each", func(t *testing.T) {
-				var items []*TestType
+			t.Run("Upsert", func(t *testing.T) {
 				set := tc.newSet()
-				set.Add(testItems...)
 
-				set.Each(func(item *TestType) bool {
-					if item.ID == 1 {
-						items = append(items, item)
-						return false
-					}
-					return true
-				})
+				stored, replaced := set.Upsert(testItems[0])
+				assert.Same(t, testItems[0], stored)
+				assert.False(t, replaced)
 
-				assert.Len(t, items, 1)
-				assert.Equal(t, items[0], testItems[5])
+				// the more important element wins and is reported as stored
+				stored, replaced = set.Upsert(testItems[5])
+				assert.Same(t, testItems[5], stored)
+				assert.True(t, replaced)
 
-				set.Each(func(item *TestType) bool {
-					items = append(items, item)
-					return true
-				})
-				assert.Len(t, items, 4)
+				// a less important element loses to the one already stored
+				stored, replaced = set.Upsert(testItems[4])
+				assert.Same(t, testItems[5], stored)
+				assert.False(t, replaced)
 
-				expectedItems := []*TestType{testItems[5], testItems[5], testItems[3], testItems[2]}
-				sortTestItems(items)
-				assert.EqualValues(t, expectedItems, items)
+				assert.Equal(t, 1, set.Len())
+				assert.EqualValues(t, []*TestType{testItems[5]}, set.ToSlice())
 			})
 
-			t.Run("Intersect", func(t *testing.T) {
-				setA := tc.newSet()
-				setA.Add(testItems...)
-
-				setB := tc.newSet()
-				setB.Add(testItems[5], testItems[2], testItems[4], &TestType{ID: 100, Name: "One Hundred", Importance: 1},
-					&TestType{ID: 200, Name: "Two Hundred", Importance: 1})
+			t.Run("Get/RemoveKey", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				item, ok := set.Get(1)
+				assert.True(t, ok)
+				assert.Same(t, testItems[5], item)
+				item, ok = set.Get(2)
+				assert.True(t, ok)
+				assert.Same(t, testItems[3], item)
+				_, ok = set.Get(4)
+				assert.False(t, ok)
+
+				set.RemoveKey(1, 3, 4)
+				assert.Equal(t, 1, set.Len())
+				_, ok = set.Get(1)
+				assert.False(t, ok)
+				assert.EqualValues(t, []*TestType{testItems[3]}, set.ToSlice())
+
+				set.RemoveKey()
+				assert.Equal(t, 1, set.Len())
+			})
+
+			t.Run("KeyFunc/ResolverFunc", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems[0], testItems[2])
+
+				key := set.KeyFunc()
+				assert.Equal(t, testItems[3].ID, key(testItems[3]))
+				assert.Contains(t, set.RemoveReturning(&TestType{ID: key(testItems[5])}), testItems[0])
+
+				resolver := set.ResolverFunc()
+				resolved, replace := resolver(testItems[4], testItems[5])
+				assert.True(t, replace)
+				assert.Same(t, testItems[5], resolved)
+
+				// a set sharing the keying of the original but keeping the first element per key
+				firstWins := goset.NewThreadUnsafeResolvingSet(set.KeyFunc(), nil)
+				firstWins.Add(testItems[0], testItems[5])
+				assert.EqualValues(t, []*TestType{testItems[0]}, firstWins.ToSlice())
+			})
+
+			t.Run("WouldAdd", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems[0], testItems[3])
+
+				newItem := &TestType{ID: 100, Name: "One Hundred", Importance: 1}
+				assert.True(t, set.WouldAdd(newItem))
+				// testItems[5] is more important than testItems[0], so it would replace it
+				assert.True(t, set.WouldAdd(testItems[5]))
+				assert.False(t, set.WouldAdd(testItems[1]))
+				assert.False(t, set.WouldAdd(testItems[0]))
+
+				actualItems := set.ToSlice()
+				sortTestItems(actualItems)
+				assert.EqualValues(t, []*TestType{testItems[0], testItems[3]}, actualItems)
+			})
+
+			t.Run("Any/All", func(t *testing.T) {
+				set := tc.newSet()
+				important := func(item *TestType) bool { return item.Importance >= 2 }
+
+				assert.False(t, set.Any(important))
+				assert.True(t, set.All(important))
+
+				set.Add(testItems[4], testItems[3])
+				assert.True(t, set.Any(important))
+				assert.True(t, set.All(important))
+
+				set.Add(testItems[2])
+				assert.True(t, set.Any(important))
+				assert.False(t, set.All(important))
+
+				set.Remove(testItems[4], testItems[3])
+				assert.False(t, set.Any(important))
+				assert.False(t, set.All(important))
+			})
+
+			t.Run("Filter", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				important := set.Filter(func(item *TestType) bool { return item.Importance >= 2 })
+				actualItems := important.ToSlice()
+				sortTestItems(actualItems)
+				assert.EqualValues(t, []*TestType{testItems[5], testItems[3]}, actualItems)
+
+				// the resolver still decides conflicts in the filtered set
+				assert.False(t, important.Add(testItems[4]))
+				assert.True(t, important.Add(testItems[2]))
+				assert.Equal(t, 3, important.Len())
+				important.Remove(testItems[5])
+				assert.Contains(t, set.ToSlice(), testItems[5])
+				assert.Equal(t, 3, set.Len())
+			})
+
+			t.Run("Partition", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				important, rest := set.Partition(func(item *TestType) bool { return item.Importance >= 2 })
+				importantItems := important.ToSlice()
+				sortTestItems(importantItems)
+				assert.EqualValues(t, []*TestType{testItems[5], testItems[3]}, importantItems)
+				assert.EqualValues(t, []*TestType{testItems[2]}, rest.ToSlice())
+
+				// both results keep the keyGetter and resolver of the original
+				assert.False(t, important.Add(testItems[4]))
+				assert.True(t, rest.Add(&TestType{ID: 3, Name: "Three", Importance: 2}))
+				assert.Equal(t, 1, rest.Len())
+				assert.Equal(t, 2, rest.ToSlice()[0].Importance)
+				assert.Contains(t, set.ToSlice(), testItems[2])
+			})
+
+			t.Run("Contains", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+				assert.True(t, set.Contains(testItems[5], testItems[3], testItems[2]))
+				assert.True(t, set.Contains(testItems[3]))
+				assert.True(t, set.Contains(testItems...))
+				assert.False(t, set.Contains(&TestType{ID: 100, Name: "One Hundred", Importance: 1}))
+			})
+
+			t.Run("ContainsAny", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems[0], testItems[1])
+				newItem := &TestType{ID: 100, Name: "One Hundred", Importance: 1}
+
+				assert.True(t, set.ContainsAny(newItem, testItems[1]))
+				// items are matched by key
+				assert.True(t, set.ContainsAny(testItems[5]))
+				assert.False(t, set.ContainsAny(newItem, testItems[2]))
+				assert.False(t, set.ContainsAny())
+			})
+
+			t.Run("Diff/SymmetricDiff", func(t *testing.T) {
+				setA := tc.newSet()
+				setA.Add(testItems...)
+
+				setB := tc.newSet()
+				setB.Add(testItems[0], testItems[2], testItems[4], &TestType{ID: 100, Name: "One Hundred", Importance: 1})
+
+				diff := setA.Diff(setB)
+				expectedItems := []*TestType{testItems[3]}
+				actualItems := diff.ToSlice()
+				sortTestItems(actualItems)
+				assert.EqualValues(t, expectedItems, actualItems)
+
+				diff = setB.Diff(setA)
+				expectedItems = []*TestType{{ID: 100, Name: "One Hundred", Importance: 1}}
+				actualItems = diff.ToSlice()
+				sortTestItems(actualItems)
+				assert.EqualValues(t, expectedItems, actualItems)
+
+				diff = setA.SymmetricDiff(setB)
+				expectedItems = []*TestType{testItems[3], {ID: 100, Name: "One Hundred", Importance: 1}}
+				actualItems = diff.ToSlice()
+				sortTestItems(actualItems)
+				assert.EqualValues(t, expectedItems, actualItems)
+			})
+
+			t.Run("Each", func(t *testing.T) {
+				var items []*TestType
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				set.Each(func(item *TestType) bool {
+					if item.ID == 1 {
+						items = append(items, item)
+						return false
+					}
+					return true
+				})
+
+				assert.Len(t, items, 1)
+				assert.Equal(t, items[0], testItems[5])
+
+				set.Each(func(item *TestType) bool {
+					items = append(items, item)
+					return true
+				})
+				assert.Len(t, items, 4)
+
+				expectedItems := []*TestType{testItems[5], testItems[5], testItems[3], testItems[2]}
+				sortTestItems(items)
+				assert.EqualValues(t, expectedItems, items)
+			})
+
+			t.Run("EachMutable", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				set.EachMutable(func(item *TestType) bool {
+					return item.Importance > 2
+				})
+				assert.Equal(t, 1, set.Len())
+				assert.EqualValues(t, []*TestType{testItems[5]}, set.ToSlice())
+			})
+
+			t.Run("Intersect", func(t *testing.T) {
+				setA := tc.newSet()
+				setA.Add(testItems...)
+
+				setB := tc.newSet()
+				setB.Add(testItems[5], testItems[2], testItems[4], &TestType{ID: 100, Name: "One Hundred", Importance: 1},
+					&TestType{ID: 200, Name: "Two Hundred", Importance: 1})
 
 				intersect := setB.Intersect(setA)
 				assert.Len(t, intersect.ToSlice(), 2)
@@ -370,6 +1047,27 @@ func TestResolvingSets(t *testing.T) {
 				assert.Contains(t, intersect.ToSlice(), testItems[5])
 			})
 
+			t.Run("IntersectWith", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				// items are matched by key
+				other := tc.newSet()
+				other.Add(testItems[0], testItems[1])
+				set.IntersectWith(other)
+				actualItems := set.ToSlice()
+				sortTestItems(actualItems)
+				assert.EqualValues(t, []*TestType{testItems[5], testItems[3]}, actualItems)
+
+				set.IntersectWith(set.Clone())
+				assert.Equal(t, 2, set.Len())
+
+				disjoint := tc.newSet()
+				disjoint.Add(testItems[2])
+				set.IntersectWith(disjoint)
+				assert.Zero(t, set.Len())
+			})
+
 			t.Run("IsSubset/IsProperSubset/IsSuperset/IsProperSuperset", func(t *testing.T) {
 				setA := tc.newSet()
 				setA.Add(testItems...)
@@ -408,6 +1106,20 @@ func TestResolvingSets(t *testing.T) {
 				_, _ = setB.Pop()
 				setB.Add(&TestType{ID: 100, Name: "One Hundred", Importance: 1})
 				assert.False(t, setA.Equal(setB))
+
+				// same keys, but the stored items differ in importance
+				setC := tc.newSet()
+				setC.Add(testItems[0], testItems[1])
+				setD := tc.newSet()
+				setD.Add(testItems[5], testItems[1])
+				assert.False(t, setC.Equal(setD))
+				assert.False(t, setD.Equal(setC))
+
+				// neither of two equally important items is less than the other
+				setE := tc.newSet()
+				setE.Add(&TestType{ID: 1, Name: "Uno", Importance: 1}, testItems[1])
+				assert.True(t, setC.Equal(setE))
+				assert.True(t, setE.Equal(setC))
 			})
 
 			t.Run("Pop", func(t *testing.T) {
@@ -415,6 +1127,45 @@ func TestResolvingSets(t *testing.T) {
 				v, ok := set.Pop()
 				assert.False(t, ok)
 				assert.Nil(t, v)
+
+				// the resolver prefers more important items, so they are popped first
+				for _, importance := range []int{3, 1, 4, 2} {
+					set.Add(&TestType{ID: 10 + importance, Name: "Ranked", Importance: importance})
+				}
+				for _, importance := range []int{4, 3, 2, 1} {
+					v, ok = set.Pop()
+					assert.True(t, ok)
+					assert.Equal(t, importance, v.Importance)
+				}
+				_, ok = set.Pop()
+				assert.False(t, ok)
+			})
+
+			t.Run("PopN", func(t *testing.T) {
+				set := tc.newSet()
+				assert.Empty(t, set.PopN(2))
+
+				for _, importance := range []int{3, 1, 4, 2} {
+					set.Add(&TestType{ID: 10 + importance, Name: "Ranked", Importance: importance})
+				}
+				var importances []int
+				for _, item := range set.PopN(3) {
+					importances = append(importances, item.Importance)
+				}
+				assert.EqualValues(t, []int{4, 3, 2}, importances)
+				assert.Equal(t, 1, set.Len())
+
+				set.Clear()
+				for i := 0; i < 200; i++ {
+					set.Add(&TestType{ID: 100 + i, Name: "Ranked", Importance: (i * 37) % 200})
+				}
+				clone := set.Clone()
+				for _, item := range set.PopN(50) {
+					next, ok := clone.Pop()
+					assert.True(t, ok)
+					assert.Same(t, next, item)
+				}
+				assert.Equal(t, 150, set.Len())
 			})
 
 			t.Run("Iter", func(t *testing.T) {
@@ -425,6 +1176,51 @@ func TestResolvingSets(t *testing.T) {
 				}
 			})
 
+			t.Run("Consume", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				var consumed []*TestType
+				for item := range set.Consume() {
+					consumed = append(consumed, item)
+				}
+				sortTestItems(consumed)
+				assert.EqualValues(t, []*TestType{testItems[5], testItems[3], testItems[2]}, consumed)
+				assert.Zero(t, set.Len())
+			})
+
+			t.Run("IterContext", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				var actualItems []*TestType
+				for item := range set.IterContext(context.Background()) {
+					actualItems = append(actualItems, item)
+				}
+				sortTestItems(actualItems)
+				assert.EqualValues(t, []*TestType{testItems[5], testItems[3], testItems[2]}, actualItems)
+			})
+
+			t.Run("Iterator", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				var actualItems []*TestType
+				for item := range set.Iterator() {
+					actualItems = append(actualItems, item)
+				}
+				sortTestItems(actualItems)
+				assert.EqualValues(t, []*TestType{testItems[5], testItems[3], testItems[2]}, actualItems)
+
+				visited := 0
+				for range set.Iterator() {
+					visited++
+					break
+				}
+				assert.Equal(t, 1, visited)
+				assert.True(t, set.Add(&TestType{ID: 100, Name: "One Hundred", Importance: 1}))
+			})
+
 			t.Run("Len", func(t *testing.T) {
 				set := tc.newSet()
 				assert.Equal(t, set.Len(), 0)
@@ -467,10 +1263,927 @@ func TestResolvingSets(t *testing.T) {
 				assert.Equal(t, 1, set.Len())
 				assert.Equal(t, testItems[3], set.ToSlice()[0])
 			})
+
+			t.Run("Update", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+				assert.Contains(t, set.ToSlice(), testItems[5])
+
+				// a lower importance item loses on Add but always wins on Update
+				assert.False(t, set.Add(testItems[0]))
+				assert.True(t, set.Update(testItems[0]))
+				assert.Equal(t, 3, set.Len())
+				assert.Contains(t, set.ToSlice(), testItems[0])
+				assert.NotContains(t, set.ToSlice(), testItems[5])
+
+				newItem := &TestType{ID: 100, Name: "One Hundred", Importance: 1}
+				assert.False(t, set.Update(newItem))
+				assert.Equal(t, 4, set.Len())
+				assert.Contains(t, set.ToSlice(), newItem)
+			})
+
+			t.Run("ToSortedSlice", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				byID := func(a, b *TestType) bool { return a.ID < b.ID }
+				assert.EqualValues(t, []*TestType{testItems[5], testItems[3], testItems[2]}, set.ToSortedSlice(byID))
+			})
+
+			t.Run("UnionWith", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems[0], testItems[1])
+
+				// the resolver decides conflicts with the incoming items
+				other := tc.newSet()
+				other.Add(testItems[3], testItems[2])
+				assert.True(t, set.UnionWith(other))
+				assert.False(t, set.UnionWith(other))
+
+				actualItems := set.ToSlice()
+				sortTestItems(actualItems)
+				assert.EqualValues(t, []*TestType{testItems[0], testItems[3], testItems[2]}, actualItems)
+			})
+
+			t.Run("RemoveSet", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				// Elements are removed by key, so a different value with the same ID is removed too.
+				sameKey := &TestType{ID: testItems[5].ID, Name: "Other", Importance: 0}
+				set.RemoveSet(goset.NewThreadUnsafeSet(sameKey, testItems[2]))
+				assert.EqualValues(t, []*TestType{testItems[3]}, set.ToSlice())
+			})
+
+			t.Run("ReplaceAll", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems[0], testItems[2])
+
+				set.ReplaceAll(testItems[3:])
+				actualItems := set.ToSlice()
+				sortTestItems(actualItems)
+				assert.EqualValues(t, []*TestType{testItems[5], testItems[3]}, actualItems)
+			})
+
+			t.Run("Toggle", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems[5], testItems[2])
+
+				newItem := &TestType{ID: 100, Name: "One Hundred", Importance: 1}
+				assert.Equal(t, 0, set.Toggle(testItems[0], newItem))
+				assert.Equal(t, 2, set.Len())
+				assert.True(t, set.Contains(testItems[2], newItem))
+				assert.False(t, set.Contains(testItems[5]))
+			})
+
+			t.Run("EachKeyed", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				items := make(map[int]*TestType)
+				goset.EachKeyed(set, func(key int, item *TestType) bool {
+					items[key] = item
+					return true
+				})
+				assert.Equal(t, map[int]*TestType{1: testItems[5], 2: testItems[3], 3: testItems[2]}, items)
+
+				count := 0
+				goset.EachKeyed(set, func(key int, item *TestType) bool {
+					count++
+					return false
+				})
+				assert.Equal(t, 1, count)
+			})
+
+			t.Run("RemoveReturning", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				removed := set.RemoveReturning(testItems[0], &TestType{ID: 100, Name: "One Hundred", Importance: 1})
+				assert.Equal(t, []*TestType{testItems[5]}, removed)
+				assert.Equal(t, 2, set.Len())
+				assert.False(t, set.Contains(testItems[0]))
+
+				assert.Empty(t, set.RemoveReturning(testItems[0]))
+			})
+
+			t.Run("AppendTo", func(t *testing.T) {
+				set := tc.newSet()
+				set.Add(testItems...)
+
+				actualItems := set.AppendTo([]*TestType{testItems[0]})
+				assert.Len(t, actualItems, 4)
+				assert.Equal(t, testItems[0], actualItems[0])
+				sortTestItems(actualItems[1:])
+				assert.EqualValues(t, []*TestType{testItems[0], testItems[5], testItems[3], testItems[2]}, actualItems)
+			})
+		})
+	}
+}
+
+func intLess(a, b int) bool { return a < b }
+
+// intSetFactories builds every implementation of Set[int], for tests that mix implementations.
+var intSetFactories = []struct {
+	name   string
+	newSet func(v ...int) goset.Set[int]
+}{
+	{
+		name:   "UnsafeSimpleSet",
+		newSet: func(v ...int) goset.Set[int] { return goset.NewThreadUnsafeSet(v...) },
+	},
+	{
+		name:   "SafeSimpleSet",
+		newSet: func(v ...int) goset.Set[int] { return goset.NewSet(v...) },
+	},
+	{
+		name: "UnsafeResolvingSet",
+		newSet: func(v ...int) goset.Set[int] {
+			set := goset.NewThreadUnsafeResolvingSet[int, int](func(v int) int { return v }, nil)
+			set.Add(v...)
+			return set
+		},
+	},
+	{
+		name: "SafeResolvingSet",
+		newSet: func(v ...int) goset.Set[int] {
+			set := goset.NewResolvingSet[int, int](func(v int) int { return v }, nil)
+			set.Add(v...)
+			return set
+		},
+	},
+	{
+		name:   "AdaptiveSet",
+		newSet: func(v ...int) goset.Set[int] { return goset.NewAdaptiveSet(v...) },
+	},
+	{
+		name:   "BitSet",
+		newSet: func(v ...int) goset.Set[int] { return goset.NewBitSet(0, v...) },
+	},
+	{
+		name:   "OrderedSet",
+		newSet: func(v ...int) goset.Set[int] { return goset.NewOrderedSet(v...) },
+	},
+	{
+		name:   "SortedSet",
+		newSet: func(v ...int) goset.Set[int] { return goset.NewSortedSet(intLess, v...) },
+	},
+	{
+		name: "MirroredSet",
+		newSet: func(v ...int) goset.Set[int] {
+			return goset.NewMirroredSet(goset.NewSet(v...), nil, nil)
+		},
+	},
+}
+
+func TestEqualAcrossImplementations(t *testing.T) {
+	contents := []struct {
+		name  string
+		items []int
+		equal bool
+		other []int
+	}{
+		{name: "BothEmpty", items: nil, other: nil, equal: true},
+		{name: "OneEmpty", items: []int{1}, other: nil, equal: false},
+		{name: "Same", items: []int{1, 2, 3}, other: []int{3, 2, 1}, equal: true},
+		{name: "SameLenDifferentItems", items: []int{1, 2, 3}, other: []int{1, 2, 4}, equal: false},
+		{name: "Subset", items: []int{1, 2}, other: []int{1, 2, 3}, equal: false},
+	}
+
+	for _, a := range intSetFactories {
+		for _, b := range intSetFactories {
+			for _, c := range contents {
+				t.Run(a.name+"/"+b.name+"/"+c.name, func(t *testing.T) {
+					setA := a.newSet(c.items...)
+					setB := b.newSet(c.other...)
+					assert.Equal(t, c.equal, setA.Equal(setB))
+					assert.Equal(t, c.equal, setB.Equal(setA))
+				})
+			}
+		}
+	}
+}
+
+func TestSetWithCapacity(t *testing.T) {
+	constructors := []struct {
+		name   string
+		newSet func(capacity int, v ...int) goset.Set[int]
+	}{
+		{name: "Safe", newSet: goset.NewSetWithCapacity[int]},
+		{name: "Unsafe", newSet: goset.NewThreadUnsafeSetWithCapacity[int]},
+	}
+
+	for _, c := range constructors {
+		for _, capacity := range []int{-1, 0, 1, 100} {
+			t.Run(fmt.Sprintf("%s/%d", c.name, capacity), func(t *testing.T) {
+				set := c.newSet(capacity, 1, 2, 3, 3)
+				assert.Equal(t, 3, set.Len())
+				assert.True(t, set.Contains(1, 2, 3))
+
+				set.Add(4, 5)
+				assert.Equal(t, 5, set.Len())
+				assert.True(t, set.Equal(goset.NewSet(1, 2, 3, 4, 5)))
+
+				assert.Zero(t, c.newSet(capacity).Len())
+			})
+		}
+	}
+}
+
+type containsCountingSet struct {
+	goset.Set[int]
+	calls int
+}
+
+func (s *containsCountingSet) Contains(v ...int) bool {
+	s.calls += len(v)
+	return s.Set.Contains(v...)
+}
+
+func TestIntersectIteratesSmallerSet(t *testing.T) {
+	huge := &containsCountingSet{Set: goset.NewAdaptiveSet[int]()}
+	for i := 0; i < 10_000; i++ {
+		huge.Add(i)
+	}
+
+	for _, tiny := range intSetFactories {
+		t.Run(tiny.name, func(t *testing.T) {
+			huge.calls = 0
+			intersection := tiny.newSet(1, 2, -3).Intersect(huge)
+			assert.Equal(t, 2, intersection.Len())
+			assert.LessOrEqual(t, huge.calls, 3)
+		})
+	}
+}
+
+func TestDisjointStopsAtFirstSharedElement(t *testing.T) {
+	huge := &containsCountingSet{Set: goset.NewAdaptiveSet[int]()}
+	for i := 0; i < 10_000; i++ {
+		huge.Add(i)
+	}
+
+	for _, tiny := range intSetFactories {
+		t.Run(tiny.name, func(t *testing.T) {
+			huge.calls = 0
+			assert.False(t, tiny.newSet(1, 2, 3).Disjoint(huge))
+			assert.Equal(t, 1, huge.calls)
+
+			huge.calls = 0
+			assert.True(t, tiny.newSet(20_000, 30_000).Disjoint(huge))
+			assert.Equal(t, 2, huge.calls)
+		})
+	}
+}
+
+func TestSetStats(t *testing.T) {
+	assert.Equal(t, goset.SetStats{Len: 2, MapBacked: true}, goset.NewSet(1, 2).Stats())
+	assert.Equal(t, goset.SetStats{IsEmpty: true, MapBacked: true}, goset.NewThreadUnsafeSet[int]().Stats())
+
+	adaptive := goset.NewAdaptiveSet(1, 2, 3)
+	assert.False(t, adaptive.Stats().MapBacked)
+	for i := 0; i < 20; i++ {
+		adaptive.Add(i)
+	}
+	assert.True(t, adaptive.Stats().MapBacked)
+	assert.Zero(t, adaptive.Stats().Capacity)
+
+	bits := goset.NewThreadUnsafeBitSet(1000, 5)
+	stats := bits.Stats()
+	assert.Equal(t, 1, stats.Len)
+	assert.False(t, stats.MapBacked)
+	assert.GreaterOrEqual(t, stats.Capacity, 1000)
+}
+
+func TestSafeUnionIsThreadSafe(t *testing.T) {
+	union := goset.NewSet(-1, -2).Union(goset.NewSet(-3))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				union.Add(offset*100 + j)
+				union.Contains(-1)
+				union.Len()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 803, union.Len())
+}
+
+func TestIterContextBufferIsCapped(t *testing.T) {
+	allocated := func(n int) uint64 {
+		set := goset.NewThreadUnsafeSetWithCapacity[int](n)
+		for i := 0; i < n; i++ {
+			set.Add(i)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		ch := set.IterContext(ctx)
+		runtime.ReadMemStats(&after)
+
+		cancel()
+		for range ch {
+		}
+		return after.TotalAlloc - before.TotalAlloc
+	}
+
+	small := allocated(10_000)
+	large := allocated(1_000_000)
+	// a buffer of a million ints alone would take 8MB
+	assert.Less(t, large, uint64(256<<10))
+	assert.Less(t, large, 2*small+(64<<10))
+}
+
+func TestRemoveSetIsThreadSafe(t *testing.T) {
+	set := goset.NewSet[int]()
+	evens := goset.NewSet[int]()
+	for i := 0; i < 1000; i++ {
+		set.Add(i)
+		if i%2 == 0 {
+			evens.Add(i)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			set.RemoveSet(evens)
+		}()
+		go func() {
+			defer wg.Done()
+			evens.Contains(0)
+			set.Len()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 500, set.Len())
+	assert.False(t, set.Contains(0))
+	assert.True(t, set.Contains(1))
+}
+
+func TestSafeResolvingSetCrossOperationsAreThreadSafe(t *testing.T) {
+	newSet := func(ids ...int) goset.ResolvingSet[*TestType, int] {
+		set := goset.NewResolvingSet(func(item *TestType) int { return item.ID }, goset.MaxResolver(
+			goset.ByKey(func(item *TestType) int { return item.Importance })))
+		for _, id := range ids {
+			set.Add(&TestType{ID: id, Name: strconv.Itoa(id), Importance: 1})
+		}
+		return set
+	}
+	var idsA, idsB []int
+	for i := 0; i < 100; i++ {
+		idsA = append(idsA, i)
+		idsB = append(idsB, i+50)
+	}
+	setA, setB := newSet(idsA...), newSet(idsB...)
+
+	// writers only add elements outside the overlap, so every intersection sees the same 50 elements
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(4)
+		go func(offset int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				setA.Add(&TestType{ID: 1000 + offset*100 + j, Importance: 1})
+			}
+		}(i)
+		go func(offset int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				setB.Add(&TestType{ID: 2000 + offset*100 + j, Importance: 1})
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				assert.Equal(t, 50, setA.Intersect(setB).Len())
+				assert.Equal(t, 50, setA.IntersectLen(setB))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				assert.Equal(t, 50, setB.Intersect(setA).Len())
+				assert.False(t, setB.Disjoint(setA))
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 500, setA.Len())
+	assert.Equal(t, 500, setB.Len())
+	assert.Equal(t, 50, setA.IntersectLen(setB))
+}
+
+func TestSafeResolvingSetMixedWithSafeSimpleSet(t *testing.T) {
+	resolving := goset.NewResolvingSet[int, int](func(v int) int { return v }, nil)
+	simple := goset.NewSet[int]()
+	resolving.Add(0, 1, 2, 3)
+	simple.Add(2, 3, 4, 5)
+
+	assert.EqualValues(t, []int{2, 3}, resolving.Intersect(simple).ToSortedSlice(intLess))
+	assert.EqualValues(t, []int{2, 3}, simple.Intersect(resolving).ToSortedSlice(intLess))
+	assert.EqualValues(t, []int{0, 1}, resolving.Diff(simple).ToSortedSlice(intLess))
+	assert.False(t, resolving.Equal(simple))
+
+	// in-place operations in opposite directions take the locks of both sets in the same order
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(3)
+		go func(offset int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				resolving.AddSet(simple)
+			}
+			simple.Add(100 + offset)
+		}(i)
+		go func(offset int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				simple.AddSet(resolving)
+			}
+			resolving.Add(200 + offset)
+		}(i)
+		go func() {
+			defer wg.Done()
+			resolving.Union(simple)
+			simple.Intersect(resolving)
+		}()
+	}
+	wg.Wait()
+
+	resolving.AddSet(simple)
+	simple.AddSet(resolving)
+	assert.True(t, resolving.Equal(simple))
+	assert.True(t, simple.Equal(resolving))
+	assert.Equal(t, 22, simple.Len())
+}
+
+func TestSafeSetOperationsWithViewsOfItself(t *testing.T) {
+	operations := map[string]func(set goset.Set[int]){
+		"AddSetFrozen":          func(set goset.Set[int]) { set.AddSet(goset.Freeze(set)) },
+		"IntersectWithMirrored": func(set goset.Set[int]) { set.IntersectWith(goset.NewMirroredSet(set, nil, nil)) },
+		"RemoveSetFrozen":       func(set goset.Set[int]) { set.RemoveSet(goset.Freeze(set)) },
+	}
+	for name, operate := range operations {
+		t.Run(name, func(t *testing.T) {
+			set := goset.NewSet(1, 2, 3)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				operate(set)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("deadlocked")
+			}
 		})
 	}
 }
 
+func TestIterDoesNotLeak(t *testing.T) {
+	identity := func(v int) int { return v }
+	factories := []struct {
+		name   string
+		newSet func() goset.Set[int]
+	}{
+		{name: "UnsafeSimpleSet", newSet: func() goset.Set[int] { return goset.NewThreadUnsafeSet[int]() }},
+		{name: "SafeSimpleSet", newSet: func() goset.Set[int] { return goset.NewSet[int]() }},
+		{name: "UnsafeResolvingSet", newSet: func() goset.Set[int] { return goset.NewThreadUnsafeResolvingSet[int, int](identity, nil) }},
+		{name: "SafeResolvingSet", newSet: func() goset.Set[int] { return goset.NewResolvingSet[int, int](identity, nil) }},
+		{name: "AdaptiveSet", newSet: func() goset.Set[int] { return goset.NewAdaptiveSet[int]() }},
+		{name: "BitSet", newSet: func() goset.Set[int] { return goset.NewBitSet(0) }},
+		{name: "OrderedSet", newSet: func() goset.Set[int] { return goset.NewOrderedSet[int]() }},
+		{name: "SortedSet", newSet: func() goset.Set[int] { return goset.NewSortedSet(func(a, b int) bool { return a < b }) }},
+		{name: "CloningSet", newSet: func() goset.Set[int] { return goset.NewCloningSet(identity, identity) }},
+	}
+
+	settledGoroutines := func() int {
+		time.Sleep(50 * time.Millisecond)
+		return runtime.NumGoroutine()
+	}
+
+	for _, f := range factories {
+		t.Run(f.name, func(t *testing.T) {
+			set := f.newSet()
+			for i := 0; i < 10000; i++ {
+				set.Add(i)
+			}
+			before := settledGoroutines()
+
+			var channels []<-chan int
+			for i := 0; i < 10; i++ {
+				for range set.Iter() {
+					break
+				}
+				ctx, cancel := context.WithCancel(context.Background())
+				ch := set.IterContext(ctx)
+				for range ch {
+					break
+				}
+				cancel()
+				channels = append(channels, ch)
+			}
+
+			assert.LessOrEqual(t, settledGoroutines(), before)
+			for _, ch := range channels {
+				for range ch {
+				}
+			}
+			// the set must not be left read locked
+			assert.True(t, set.Add(-1))
+
+			for elem := range set.Iter() {
+				set.Remove(elem)
+			}
+			assert.True(t, set.IsEmpty())
+		})
+	}
+}
+
+func TestSetFromMapKeys(t *testing.T) {
+	constructors := map[string]func(m map[string]int) goset.Set[string]{
+		"NewSetFromMapKeys":             goset.NewSetFromMapKeys[string, int],
+		"NewThreadUnsafeSetFromMapKeys": goset.NewThreadUnsafeSetFromMapKeys[string, int],
+	}
+	for name, newSet := range constructors {
+		t.Run(name, func(t *testing.T) {
+			assert.Zero(t, newSet(map[string]int{}).Len())
+			assert.Zero(t, newSet(nil).Len())
+			assert.True(t, goset.NewSet("a").Equal(newSet(map[string]int{"a": 1})))
+
+			m := map[string]int{"a": 1, "b": 2}
+			set := newSet(m)
+			m["c"] = 3
+			delete(m, "a")
+			assert.True(t, goset.NewSet("a", "b").Equal(set))
+		})
+	}
+}
+
+func TestSetFromChannel(t *testing.T) {
+	ch := make(chan int, 6)
+	for _, v := range []int{1, 2, 2, 3, 1, 3} {
+		ch <- v
+	}
+	close(ch)
+	assert.True(t, goset.NewSet(1, 2, 3).Equal(goset.NewSetFromChannel(ch)))
+
+	empty := make(chan int)
+	close(empty)
+	assert.Zero(t, goset.NewSetFromChannel(empty).Len())
+}
+
+func TestFromSlice(t *testing.T) {
+	byID := func(item TestType) int { return item.ID }
+	items := []TestType{
+		{ID: 1, Name: "One", Importance: 1},
+		{ID: 2, Name: "Two", Importance: 1},
+		{ID: 1, Name: "Uno", Importance: 2},
+		{ID: 1, Name: "Eins", Importance: 0},
+	}
+
+	set := goset.FromSlice(items, byID)
+	assert.Equal(t, 2, set.Len())
+	actualItems := set.ToSortedSlice(func(a, b TestType) bool { return a.ID < b.ID })
+	assert.EqualValues(t, []TestType{items[3], items[1]}, actualItems)
+
+	set.Add(TestType{ID: 2, Name: "Deux", Importance: 0})
+	assert.Contains(t, set.ToSlice(), TestType{ID: 2, Name: "Deux", Importance: 0})
+
+	assert.Zero(t, goset.FromSlice(nil, byID).Len())
+}
+
+func TestNilResolverKeepsFirstElement(t *testing.T) {
+	byID := func(item *TestType) int { return item.ID }
+	sets := map[string]goset.ResolvingSet[*TestType, int]{
+		"UnsafeResolvingSet": goset.NewThreadUnsafeResolvingSet(byID, nil),
+		"SafeResolvingSet":   goset.NewResolvingSet(byID, nil),
+	}
+
+	for name, set := range sets {
+		t.Run(name, func(t *testing.T) {
+			first := &TestType{ID: 1, Name: "One", Importance: 1}
+			second := &TestType{ID: 1, Name: "Uno", Importance: 2}
+
+			assert.True(t, set.Add(first))
+			assert.False(t, set.WouldAdd(second))
+			assert.False(t, set.Add(second))
+			assert.False(t, set.AddSet(goset.NewThreadUnsafeSet(second)))
+			assert.Equal(t, 1, set.Len())
+			assert.Same(t, first, set.ToSlice()[0])
+
+			stored, replaced := set.Upsert(second)
+			assert.Same(t, first, stored)
+			assert.False(t, replaced)
+
+			// Update bypasses the resolver
+			assert.True(t, set.Update(second))
+			assert.Same(t, second, set.ToSlice()[0])
+		})
+	}
+}
+
+func TestOnlyResolvingSetsImplementResolvingSet(t *testing.T) {
+	_, ok := goset.NewSet(1).(goset.ResolvingSet[int, struct{}])
+	assert.False(t, ok)
+	_, ok = goset.NewSortedSet(intLess, 1).(goset.ResolvingSet[int, struct{}])
+	assert.False(t, ok)
+
+	byID := func(item *TestType) int { return item.ID }
+	set := goset.NewResolvingSet(byID, nil)
+	set.Add(testItems...)
+	isEven := func(item *TestType) bool { return item.ID%2 == 0 }
+	matching, rest := set.Partition(isEven)
+	results := map[string]goset.Set[*TestType]{
+		"Clone":         set.Clone(),
+		"CloneWith":     set.CloneWith(func(item *TestType) *TestType { return item }),
+		"Filter":        set.Filter(isEven),
+		"Partition":     matching,
+		"PartitionRest": rest,
+		"Diff":          set.Diff(goset.NewSet[*TestType]()),
+		"Intersect":     set.Intersect(set.Clone()),
+		"Union":         set.Union(goset.NewSet[*TestType]()),
+		"SymmetricDiff": set.SymmetricDiff(goset.NewSet[*TestType]()),
+	}
+	for name, result := range results {
+		t.Run(name, func(t *testing.T) {
+			resolving, ok := result.(goset.ResolvingSet[*TestType, int])
+			if assert.True(t, ok) {
+				_, found := resolving.Get(result.ToSlice()[0].ID)
+				assert.True(t, found)
+			}
+		})
+	}
+}
+
+func TestReplaceAllIsAtomic(t *testing.T) {
+	var oldItems, newItems []int
+	for i := 0; i < 100; i++ {
+		oldItems = append(oldItems, i)
+	}
+	for i := 100; i < 150; i++ {
+		newItems = append(newItems, i)
+	}
+	oldSet := goset.NewThreadUnsafeSet(oldItems...)
+	newSet := goset.NewThreadUnsafeSet(newItems...)
+
+	set := goset.NewSet(oldItems...)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				snapshot := goset.NewThreadUnsafeSet(set.ToSlice()...)
+				if !snapshot.Equal(oldSet) && !snapshot.Equal(newSet) {
+					assert.Fail(t, "reader saw a partially replaced set", "%d elements", snapshot.Len())
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		if i%2 == 0 {
+			set.ReplaceAll(newItems)
+		} else {
+			set.ReplaceAll(oldItems)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestReplaceAllKeepsPrefilterConsistent(t *testing.T) {
+	var oldItems, newItems []int
+	for i := 0; i < 100; i++ {
+		oldItems = append(oldItems, i)
+		newItems = append(newItems, i+50)
+	}
+
+	set := goset.NewSetWithOptions[int](goset.WithBloomPrefilter(1000, 0.01))
+	set.Add(oldItems...)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				// 75 is in both the old and new items, so it must never appear missing
+				if !set.Contains(75) || !set.ContainsAny(75) {
+					assert.Fail(t, "reader missed an element kept by ReplaceAll")
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 2000; i++ {
+		if i%2 == 0 {
+			set.ReplaceAll(newItems)
+		} else {
+			set.ReplaceAll(oldItems)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestAdaptiveSet(t *testing.T) {
+	set := goset.NewAdaptiveSet[int]()
+	expected := goset.NewThreadUnsafeSet[int]()
+
+	// grow well past the slice-to-map threshold, then shrink back below the map-to-slice threshold
+	for i := 0; i < 40; i++ {
+		assert.True(t, set.Add(i))
+		assert.False(t, set.Add(i))
+		expected.Add(i)
+		assert.True(t, set.Equal(expected))
+	}
+	for i := 0; i < 38; i++ {
+		set.Remove(i)
+		expected.Remove(i)
+		assert.Equal(t, expected.Len(), set.Len())
+		assert.True(t, set.Equal(expected))
+		assert.False(t, set.Contains(i))
+	}
+
+	set.Add(1, 2, 3)
+	assert.Equal(t, 5, set.Len())
+	assert.Equal(t, 1, set.Toggle(4, 5, 38, 39, 6))
+	assert.True(t, set.Contains(1, 2, 3, 4, 5, 6))
+	assert.False(t, set.Contains(38, 39))
+
+	for i := 100; i < 120; i++ {
+		set.Add(i)
+	}
+	set.EachMutable(func(v int) bool { return v < 100 })
+	assert.True(t, set.Equal(goset.NewThreadUnsafeSet(1, 2, 3, 4, 5, 6)))
+}
+
+func TestOperationsAcrossImplementations(t *testing.T) {
+	sorted := func(set goset.Set[int]) []int {
+		items := set.ToSlice()
+		sort.Ints(items)
+		return items
+	}
+
+	for _, a := range intSetFactories {
+		for _, b := range intSetFactories {
+			t.Run(a.name+"/"+b.name, func(t *testing.T) {
+				setA := a.newSet(1, 2, 3, 4)
+				setB := b.newSet(3, 4, 5)
+
+				assert.EqualValues(t, []int{1, 2, 3, 4, 5}, sorted(setA.Union(setB)))
+				assert.EqualValues(t, []int{1, 2}, sorted(setA.Diff(setB)))
+				assert.EqualValues(t, []int{5}, sorted(setB.Diff(setA)))
+				assert.EqualValues(t, []int{1, 2, 5}, sorted(setA.SymmetricDiff(setB)))
+				assert.EqualValues(t, []int{3, 4}, sorted(setA.Intersect(setB)))
+				assert.EqualValues(t, []int{3, 4}, sorted(setB.Intersect(setA)))
+				assert.Equal(t, setA.Diff(setB).Len(), setA.DiffLen(setB))
+				assert.Equal(t, setB.Diff(setA).Len(), setB.DiffLen(setA))
+				assert.Equal(t, setA.Intersect(setB).Len(), setA.IntersectLen(setB))
+				assert.Equal(t, setB.Intersect(setA).Len(), setB.IntersectLen(setA))
+				assert.False(t, setA.Equal(setB))
+
+				subset := b.newSet(2, 3)
+				assert.True(t, subset.IsSubset(setA))
+				assert.True(t, subset.IsProperSubset(setA))
+				assert.True(t, setA.IsSuperset(subset))
+				assert.True(t, setA.IsProperSuperset(subset))
+				assert.False(t, setA.IsSubset(subset))
+				assert.False(t, setB.IsSubset(setA))
+			})
+		}
+	}
+}
+
+func TestResolvingSetPopWithUnorderedResolvers(t *testing.T) {
+	byID := func(item *TestType) int { return item.ID }
+	merges := 0
+	merge := goset.MergeResolver(func(foundItem, newItem *TestType) *TestType {
+		merges++
+		return &TestType{ID: foundItem.ID, Name: foundItem.Name, Importance: foundItem.Importance + newItem.Importance}
+	})
+	resolvers := map[string]goset.Resolver[*TestType]{
+		"KeepLast":      goset.KeepLast[*TestType](),
+		"MergeResolver": merge,
+	}
+	for name, resolver := range resolvers {
+		for _, newSet := range []func(goset.KeyGetter[*TestType, int], goset.Resolver[*TestType]) goset.ResolvingSet[*TestType, int]{
+			goset.NewThreadUnsafeResolvingSet[*TestType, int],
+			goset.NewResolvingSet[*TestType, int],
+		} {
+			t.Run(name, func(t *testing.T) {
+				set := newSet(byID, resolver)
+				for i := 0; i < 100; i++ {
+					set.Add(&TestType{ID: i, Importance: i % 7})
+				}
+				merges = 0
+
+				var popped []int
+				for _, item := range set.PopN(40) {
+					popped = append(popped, item.ID)
+				}
+				for item, ok := set.Pop(); ok; item, ok = set.Pop() {
+					popped = append(popped, item.ID)
+				}
+				// every element is popped exactly once, and the resolver is never asked to rank them
+				assert.Len(t, popped, 100)
+				assert.Equal(t, 100, goset.NewSet(popped...).Len())
+				assert.Zero(t, merges)
+			})
+		}
+	}
+}
+
+func TestResolvingSetEqualWithUnorderedResolver(t *testing.T) {
+	byID := func(item *TestType) int { return item.ID }
+	for _, newSet := range []func(goset.KeyGetter[*TestType, int], goset.Resolver[*TestType]) goset.ResolvingSet[*TestType, int]{
+		goset.NewThreadUnsafeResolvingSet[*TestType, int],
+		goset.NewResolvingSet[*TestType, int],
+	} {
+		setA := newSet(byID, goset.KeepLast[*TestType]())
+		setB := newSet(byID, goset.KeepLast[*TestType]())
+		setA.Add(&TestType{ID: 1, Name: "One", Importance: 1})
+		setB.Add(&TestType{ID: 1, Name: "Uno", Importance: 5})
+		assert.False(t, setA.Equal(setB))
+		assert.False(t, setB.Equal(setA))
+
+		// distinct pointers to equal values are equal
+		setB.Add(&TestType{ID: 1, Name: "One", Importance: 1})
+		assert.True(t, setA.Equal(setB))
+		assert.True(t, setB.Equal(setA))
+	}
+}
+
+func TestResolvingSetEqualIsSymmetric(t *testing.T) {
+	byID := func(item *TestType) int { return item.ID }
+	unsafeSet := goset.NewThreadUnsafeResolvingSet(byID, keepMostImportant)
+	safeSet := goset.NewResolvingSet(byID, keepMostImportant)
+
+	unsafeSet.Add(testItems[0])
+	safeSet.Add(testItems[5])
+	assert.False(t, unsafeSet.Equal(safeSet))
+	assert.False(t, safeSet.Equal(unsafeSet))
+
+	unsafeSet.Add(testItems[5])
+	assert.True(t, unsafeSet.Equal(safeSet))
+	assert.True(t, safeSet.Equal(unsafeSet))
+}
+
+func TestEquivSet(t *testing.T) {
+	sortBytes := func(v [3]byte) [3]byte {
+		sort.Slice(v[:], func(i, j int) bool { return v[i] < v[j] })
+		return v
+	}
+
+	set := goset.NewEquivSet(sortBytes, [3]byte{1, 0, 0}, [3]byte{0, 1, 0})
+	assert.Equal(t, 1, set.Len())
+	assert.Equal(t, [][3]byte{{0, 0, 1}}, set.ToSlice())
+
+	assert.False(t, set.WouldAdd([3]byte{0, 0, 1}))
+	assert.False(t, set.Add([3]byte{0, 0, 1}))
+	assert.True(t, set.WouldAdd([3]byte{2, 0, 0}))
+	assert.True(t, set.Add([3]byte{2, 0, 0}))
+	assert.Equal(t, 2, set.Len())
+
+	assert.True(t, set.Contains([3]byte{0, 0, 1}, [3]byte{0, 2, 0}))
+	assert.False(t, set.Contains([3]byte{1, 1, 0}))
+
+	set.Remove([3]byte{0, 0, 2})
+	assert.Equal(t, 1, set.Len())
+	assert.Equal(t, [][3]byte{{0, 0, 1}}, set.ToSlice())
+
+	set.Toggle([3]byte{3, 0, 0})
+	set.ReplaceAll(append(set.ToSlice(), [3]byte{4, 0, 0}))
+	assert.ElementsMatch(t, [][3]byte{{0, 0, 1}, {0, 0, 3}, {0, 0, 4}}, set.ToSlice())
+}
+
 type TestType struct {
 	ID         int
 	Name       string
@@ -0,0 +1,88 @@
+package goset
+
+// Stability returns the fraction of elements in prev that are still present in curr, |prev ∩ curr| / |prev|.
+// It quantifies how much a set changed between two snapshots and returns 0 if prev is empty.
+func Stability[T comparable](prev, curr Set[T]) float64 {
+	if prev.IsEmpty() {
+		return 0
+	}
+	retained := 0
+	prev.Each(func(elem T) bool {
+		if curr.Contains(elem) {
+			retained++
+		}
+		return true
+	})
+	return float64(retained) / float64(prev.Len())
+}
+
+// SimilarityTracker keeps a running Jaccard similarity between a fixed reference set
+// and the distinct elements observed so far from a stream.
+// The reference set must not change while it is being tracked.
+type SimilarityTracker[T comparable] struct {
+	reference    Set[T]
+	matched      Set[T]
+	isNew        func(v T) bool
+	intersection int
+	union        int
+}
+
+// NewSimilarityTracker returns a SimilarityTracker against the given reference set, with nothing observed yet.
+// It remembers every distinct element observed, so its memory use grows with the number of distinct elements
+// in the stream; use NewSimilarityTrackerWithDedupe to bound it.
+func NewSimilarityTracker[T comparable](reference Set[T]) *SimilarityTracker[T] {
+	outside := NewThreadUnsafeSet[T]()
+	return NewSimilarityTrackerWithDedupe(reference, func(v T) bool {
+		return outside.Add(v)
+	})
+}
+
+// NewSimilarityTrackerWithDedupe returns a SimilarityTracker that only remembers the observed elements that are in
+// the reference set, so its memory use is bounded by the size of the reference set. An observed element outside
+// the reference set is counted when isNew returns true for it, which leaves detecting repeats to the caller,
+// for example with a probabilistic filter, or not at all for a stream known to hold no repeats.
+func NewSimilarityTrackerWithDedupe[T comparable](reference Set[T], isNew func(v T) bool) *SimilarityTracker[T] {
+	return &SimilarityTracker[T]{
+		reference: reference,
+		matched:   NewThreadUnsafeSet[T](),
+		isNew:     isNew,
+		union:     reference.Len(),
+	}
+}
+
+// Observe adds an element from the stream to the tracked similarity. Elements already observed are ignored.
+func (t *SimilarityTracker[T]) Observe(v T) {
+	if t.reference.Contains(v) {
+		if t.matched.Add(v) {
+			t.intersection++
+		}
+		return
+	}
+	if t.isNew(v) {
+		t.union++
+	}
+}
+
+// Jaccard returns the Jaccard similarity, |observed ∩ reference| / |observed ∪ reference|,
+// of the elements observed so far against the reference set. Two empty sets have a similarity of 1.
+func (t *SimilarityTracker[T]) Jaccard() float64 {
+	if t.union == 0 {
+		return 1
+	}
+	return float64(t.intersection) / float64(t.union)
+}
+
+// Nearest returns the element of s that minimizes dist(element, target) and true, or false if s is empty.
+// It scans every element, so it is O(n); a sorted structure could answer the same query faster for ordered elements.
+func Nearest[T any](s Set[T], target T, dist func(a, b T) float64) (T, bool) {
+	var nearest T
+	found := false
+	minDist := 0.0
+	s.Each(func(elem T) bool {
+		if d := dist(elem, target); !found || d < minDist {
+			nearest, minDist, found = elem, d, true
+		}
+		return true
+	})
+	return nearest, found
+}
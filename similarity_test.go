@@ -0,0 +1,79 @@
+package goset_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestStability(t *testing.T) {
+	prev := goset.NewSet(1, 2, 3, 4)
+
+	assert.Equal(t, 1.0, goset.Stability(prev, goset.NewSet(1, 2, 3, 4, 5)))
+	assert.Equal(t, 0.0, goset.Stability(prev, goset.NewSet(5, 6, 7, 8)))
+	assert.Equal(t, 0.75, goset.Stability(prev, goset.NewThreadUnsafeSet(2, 3, 4, 9)))
+	assert.Equal(t, 0.0, goset.Stability(goset.NewSet[int](), prev))
+}
+
+func TestSimilarityTracker(t *testing.T) {
+	reference := goset.NewSet(1, 2, 3, 4, 5)
+	tracker := goset.NewSimilarityTracker(reference)
+	assert.Equal(t, 0.0, tracker.Jaccard())
+
+	observed := goset.NewSet[int]()
+	for _, v := range []int{1, 7, 2, 2, 8, 3, 1, 9, 4, 5} {
+		tracker.Observe(v)
+		observed.Add(v)
+
+		intersection := reference.Intersect(observed).Len()
+		union := reference.Union(observed).Len()
+		assert.InDelta(t, float64(intersection)/float64(union), tracker.Jaccard(), 1e-9)
+	}
+	assert.InDelta(t, 5.0/8.0, tracker.Jaccard(), 1e-9)
+
+	assert.Equal(t, 1.0, goset.NewSimilarityTracker(goset.NewSet[int]()).Jaccard())
+}
+
+func TestSimilarityTrackerWithDedupe(t *testing.T) {
+	reference := goset.NewSet(1, 2, 3, 4, 5)
+	var checked []int
+	tracker := goset.NewSimilarityTrackerWithDedupe(reference, func(v int) bool {
+		checked = append(checked, v)
+		return v%2 == 0
+	})
+
+	for _, v := range []int{1, 1, 2, 7, 8, 9, 10} {
+		tracker.Observe(v)
+	}
+	// only elements outside the reference are deduplicated by the caller
+	assert.EqualValues(t, []int{7, 8, 9, 10}, checked)
+	assert.InDelta(t, 2.0/7.0, tracker.Jaccard(), 1e-9)
+}
+
+func TestNearest(t *testing.T) {
+	ints := goset.NewSet(1, 5, 10, 20)
+	intDist := func(a, b int) float64 { return math.Abs(float64(a - b)) }
+
+	nearest, ok := goset.Nearest(ints, 13, intDist)
+	assert.True(t, ok)
+	assert.Equal(t, 10, nearest)
+
+	nearest, ok = goset.Nearest(ints, -100, intDist)
+	assert.True(t, ok)
+	assert.Equal(t, 1, nearest)
+
+	_, ok = goset.Nearest(goset.NewSet[int](), 13, intDist)
+	assert.False(t, ok)
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := goset.NewSet(base, base.Add(time.Hour), base.Add(3*time.Hour))
+	timeDist := func(a, b time.Time) float64 { return math.Abs(float64(a.Sub(b))) }
+
+	nearestTime, ok := goset.Nearest(times, base.Add(100*time.Minute), timeDist)
+	assert.True(t, ok)
+	assert.Equal(t, base.Add(time.Hour), nearestTime)
+}
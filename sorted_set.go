@@ -0,0 +1,369 @@
+package goset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// unsafeSortedSet keeps its elements in a slice sorted by less, so lookups are a binary search and elements are
+// visited in ascending order. Two elements are equal when neither is less than the other, and adding an element equal
+// to one already present keeps the existing element. Adding and removing shift the slice, which is O(n).
+type unsafeSortedSet[T any] struct {
+	elems []T
+	less  func(a, b T) bool
+}
+
+// Assert concrete type:unsafeSortedSet adheres to Set interface.
+var _ Set[string] = (*unsafeSortedSet[string])(nil)
+
+func newUnsafeSortedSet[T any](less func(a, b T) bool) *unsafeSortedSet[T] {
+	return &unsafeSortedSet[T]{less: less}
+}
+
+func (s *unsafeSortedSet[T]) compare(a, b T) int {
+	switch {
+	case s.less(a, b):
+		return -1
+	case s.less(b, a):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// search returns the position of v in the slice, or where it would be inserted, and whether it is present.
+func (s *unsafeSortedSet[T]) search(v T) (int, bool) {
+	return slices.BinarySearchFunc(s.elems, v, s.compare)
+}
+
+func (s *unsafeSortedSet[T]) add(v T) bool {
+	i, found := s.search(v)
+	if found {
+		return false
+	}
+	s.elems = slices.Insert(s.elems, i, v)
+	return true
+}
+
+func (s *unsafeSortedSet[T]) remove(v T) bool {
+	i, found := s.search(v)
+	if !found {
+		return false
+	}
+	s.elems = slices.Delete(s.elems, i, i+1)
+	return true
+}
+
+func (s *unsafeSortedSet[T]) contains(v T) bool {
+	_, found := s.search(v)
+	return found
+}
+
+func (s *unsafeSortedSet[T]) Add(v ...T) bool {
+	var ret bool
+	for _, val := range v {
+		if s.add(val) {
+			ret = true
+		}
+	}
+	return ret
+}
+
+func (s *unsafeSortedSet[T]) AddSet(other Set[T]) bool {
+	if other == Set[T](s) {
+		return false
+	}
+	prevLen := s.Len()
+	other.Each(func(elem T) bool {
+		s.add(elem)
+		return true
+	})
+	return prevLen != s.Len()
+}
+
+func (s *unsafeSortedSet[T]) WouldAdd(v T) bool {
+	return !s.contains(v)
+}
+
+func (s *unsafeSortedSet[T]) Len() int {
+	return len(s.elems)
+}
+
+func (s *unsafeSortedSet[T]) IsEmpty() bool {
+	return len(s.elems) == 0
+}
+
+func (s *unsafeSortedSet[T]) Stats() SetStats {
+	return SetStats{Len: s.Len(), IsEmpty: s.IsEmpty(), Capacity: cap(s.elems)}
+}
+
+func (s *unsafeSortedSet[T]) Clear() {
+	clear(s.elems)
+	s.elems = s.elems[:0]
+}
+
+func (s *unsafeSortedSet[T]) Clone() Set[T] {
+	return &unsafeSortedSet[T]{
+		elems: slices.Clone(s.elems),
+		less:  s.less,
+	}
+}
+
+func (s *unsafeSortedSet[T]) CloneWith(copy func(T) T) Set[T] {
+	clone := newUnsafeSortedSet(s.less)
+	for _, elem := range s.elems {
+		clone.add(copy(elem))
+	}
+	return clone
+}
+
+func (s *unsafeSortedSet[T]) Contains(v ...T) bool {
+	for _, val := range v {
+		if !s.contains(val) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *unsafeSortedSet[T]) ContainsAny(v ...T) bool {
+	for _, val := range v {
+		if s.contains(val) {
+			return true
+		}
+	}
+	return false
+}
+
+// Each visits the elements in ascending order.
+func (s *unsafeSortedSet[T]) Each(fn func(T) bool) {
+	eachOf(s.elems, fn)
+}
+
+func (s *unsafeSortedSet[T]) EachMutable(fn func(T) (keep bool)) {
+	s.elems = slices.DeleteFunc(s.elems, func(elem T) bool {
+		return !fn(elem)
+	})
+}
+
+func (s *unsafeSortedSet[T]) EachSnapshot(fn func(T) bool) {
+	eachOf(s.ToSlice(), fn)
+}
+
+func (s *unsafeSortedSet[T]) Filter(pred func(T) bool) Set[T] {
+	filtered := newUnsafeSortedSet(s.less)
+	for _, elem := range s.elems {
+		if pred(elem) {
+			// elements arrive in order, so appending keeps the slice sorted
+			filtered.elems = append(filtered.elems, elem)
+		}
+	}
+	return filtered
+}
+
+func (s *unsafeSortedSet[T]) Partition(pred func(T) bool) (Set[T], Set[T]) {
+	matching, rest := newUnsafeSortedSet(s.less), newUnsafeSortedSet(s.less)
+	for _, elem := range s.elems {
+		if pred(elem) {
+			matching.elems = append(matching.elems, elem)
+		} else {
+			rest.elems = append(rest.elems, elem)
+		}
+	}
+	return matching, rest
+}
+
+func (s *unsafeSortedSet[T]) Any(pred func(T) bool) bool {
+	return anyOf(s.Each, pred)
+}
+
+func (s *unsafeSortedSet[T]) All(pred func(T) bool) bool {
+	return allOf(s.Each, pred)
+}
+
+func (s *unsafeSortedSet[T]) Diff(other Set[T]) Set[T] {
+	return diffByContains[T](newUnsafeSortedSet(s.less), s, other)
+}
+
+func (s *unsafeSortedSet[T]) DiffLen(other Set[T]) int {
+	return diffLenByContains[T](s, other)
+}
+
+func (s *unsafeSortedSet[T]) SymmetricDiff(other Set[T]) Set[T] {
+	return symmetricDiffByContains[T](newUnsafeSortedSet(s.less), s, other)
+}
+
+func (s *unsafeSortedSet[T]) Equal(other Set[T]) bool {
+	return equalByContains[T](s, other)
+}
+
+func (s *unsafeSortedSet[T]) Intersect(other Set[T]) Set[T] {
+	return intersectByContains[T](newUnsafeSortedSet(s.less), s, other)
+}
+
+func (s *unsafeSortedSet[T]) IntersectLen(other Set[T]) int {
+	return intersectLenByContains[T](s, other)
+}
+
+func (s *unsafeSortedSet[T]) Disjoint(other Set[T]) bool {
+	return disjointByContains[T](s, other)
+}
+
+func (s *unsafeSortedSet[T]) IntersectWith(other Set[T]) {
+	if other == Set[T](s) {
+		return
+	}
+	s.EachMutable(func(elem T) bool {
+		return other.Contains(elem)
+	})
+}
+
+func (s *unsafeSortedSet[T]) IsSubset(other Set[T]) bool {
+	return isSubsetByContains[T](s, other)
+}
+
+func (s *unsafeSortedSet[T]) IsProperSubset(other Set[T]) bool {
+	return s.Len() < other.Len() && s.IsSubset(other)
+}
+
+func (s *unsafeSortedSet[T]) IsSuperset(other Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+func (s *unsafeSortedSet[T]) IsProperSuperset(other Set[T]) bool {
+	return s.Len() > other.Len() && s.IsSuperset(other)
+}
+
+func (s *unsafeSortedSet[T]) Iter() <-chan T {
+	return sliceChan(s.ToSlice())
+}
+
+func (s *unsafeSortedSet[T]) IterContext(ctx context.Context) <-chan T {
+	return iterContext(ctx, s.Len(), s.Each)
+}
+
+func (s *unsafeSortedSet[T]) Iterator() iter.Seq[T] {
+	return s.Each
+}
+
+func (s *unsafeSortedSet[T]) Consume() <-chan T {
+	elems := s.elems
+	s.elems = nil
+	return sliceChan(elems)
+}
+
+// Pop removes the smallest element.
+func (s *unsafeSortedSet[T]) Pop() (T, bool) {
+	if len(s.elems) == 0 {
+		var zeroElem T
+		return zeroElem, false
+	}
+	elem := s.elems[0]
+	s.elems = slices.Delete(s.elems, 0, 1)
+	return elem, true
+}
+
+// PopN removes the n smallest elements.
+func (s *unsafeSortedSet[T]) PopN(n int) []T {
+	n = max(min(n, s.Len()), 0)
+	popped := slices.Clone(s.elems[:n])
+	s.elems = slices.Delete(s.elems, 0, n)
+	return popped
+}
+
+func (s *unsafeSortedSet[T]) Sample() (T, bool) {
+	if len(s.elems) == 0 {
+		var zeroElem T
+		return zeroElem, false
+	}
+	return s.elems[rand.IntN(len(s.elems))], true
+}
+
+func (s *unsafeSortedSet[T]) SampleN(n int) []T {
+	return sampleNOf(s.Len(), s.Each, n)
+}
+
+func (s *unsafeSortedSet[T]) Remove(v ...T) {
+	for _, val := range v {
+		s.remove(val)
+	}
+}
+
+func (s *unsafeSortedSet[T]) RemoveSet(other Set[T]) {
+	if other == Set[T](s) {
+		s.Clear()
+		return
+	}
+	other.Each(func(elem T) bool {
+		s.remove(elem)
+		return true
+	})
+}
+
+func (s *unsafeSortedSet[T]) RemoveIf(pred func(T) bool) int {
+	prevLen := len(s.elems)
+	s.elems = slices.DeleteFunc(s.elems, pred)
+	return prevLen - len(s.elems)
+}
+
+func (s *unsafeSortedSet[T]) ReplaceAll(items []T) {
+	s.Clear()
+	s.Add(items...)
+}
+
+func (s *unsafeSortedSet[T]) Toggle(v ...T) int {
+	prevLen := s.Len()
+	for _, val := range v {
+		if !s.remove(val) {
+			s.add(val)
+		}
+	}
+	return s.Len() - prevLen
+}
+
+func (s *unsafeSortedSet[T]) Union(other Set[T]) Set[T] {
+	return unionByContains[T](newUnsafeSortedSet(s.less), s, other)
+}
+
+func (s *unsafeSortedSet[T]) UnionWith(other Set[T]) bool {
+	return s.AddSet(other)
+}
+
+func (s *unsafeSortedSet[T]) ToSlice() []T {
+	return slices.Clone(s.elems)
+}
+
+// ToSortedSlice sorts by less rather than the order of the set.
+func (s *unsafeSortedSet[T]) ToSortedSlice(less func(a, b T) bool) []T {
+	return sortedSlice(s.ToSlice(), less)
+}
+
+func (s *unsafeSortedSet[T]) AppendTo(dst []T) []T {
+	return append(dst, s.elems...)
+}
+
+func (s *unsafeSortedSet[T]) String() string {
+	items := make([]string, 0, len(s.elems))
+	for _, elem := range s.elems {
+		items = append(items, fmt.Sprintf("%#v", elem))
+	}
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
+
+func (s *unsafeSortedSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.AppendTo(make([]T, 0, s.Len())))
+}
+
+func (s *unsafeSortedSet[T]) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalJSONElems[T](data)
+	if err != nil {
+		return err
+	}
+	s.Add(elems...)
+	return nil
+}
@@ -0,0 +1,52 @@
+package goset_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestSortedSet(t *testing.T) {
+	set := goset.NewThreadUnsafeSortedSet(intLess, 5, 1, 4, 1, 3)
+	assert.Equal(t, 4, set.Len())
+	assert.EqualValues(t, []int{1, 3, 4, 5}, set.ToSlice())
+	assert.Equal(t, "Set{1, 3, 4, 5}", set.String())
+
+	set.Add(2, 6, 0)
+	var iterated []int
+	for elem := range set.Iter() {
+		iterated = append(iterated, elem)
+	}
+	assert.EqualValues(t, []int{0, 1, 2, 3, 4, 5, 6}, iterated)
+
+	v, ok := set.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 0, v)
+	assert.EqualValues(t, []int{1, 2}, set.PopN(2))
+	set.Remove(5)
+	assert.EqualValues(t, []int{3, 4, 6}, set.ToSlice())
+
+	assert.EqualValues(t, []int{3, 4, 6, 7}, set.Union(goset.NewSet(7, 3)).ToSlice())
+	assert.EqualValues(t, []int{3, 6}, set.Diff(goset.NewSet(4)).ToSlice())
+
+	set.Clear()
+	_, ok = set.Pop()
+	assert.False(t, ok)
+}
+
+func TestSortedSetCollapsesEqualElements(t *testing.T) {
+	// less compares case-insensitively, so elements differing only in case are equal
+	less := func(a, b string) bool { return strings.ToLower(a) < strings.ToLower(b) }
+	set := goset.NewSortedSet(less, "banana", "Apple", "apple", "BANANA", "cherry")
+
+	assert.Equal(t, 3, set.Len())
+	assert.EqualValues(t, []string{"Apple", "banana", "cherry"}, set.ToSlice())
+	assert.True(t, set.Contains("APPLE", "Cherry"))
+	assert.False(t, set.Add("CHERRY"))
+
+	set.Remove("BANANA")
+	assert.EqualValues(t, []string{"Apple", "cherry"}, set.ToSlice())
+}
@@ -0,0 +1,19 @@
+package goset
+
+// Map returns a new set of fn applied to every element of s. Elements that map to the same value
+// collapse into a single element of the result.
+func Map[T any, R comparable](s Set[T], fn func(T) R) Set[R] {
+	mapped := NewSetWithCapacity[R](s.Len())
+	s.Each(func(elem T) bool {
+		mapped.Add(fn(elem))
+		return true
+	})
+	return mapped
+}
+
+// IntersectPredicate intersects s with the implicit set of every value for which inSet returns true,
+// such as all even numbers, which may be infinite or defined only by a rule. It returns a new set of the same kind
+// as s holding the elements of s that are in that set, and is equivalent to s.Filter(inSet).
+func IntersectPredicate[T any](s Set[T], inSet func(T) bool) Set[T] {
+	return s.Filter(inSet)
+}
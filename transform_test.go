@@ -0,0 +1,40 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestMap(t *testing.T) {
+	people := goset.NewThreadUnsafeSet(testItems...)
+
+	ids := goset.Map(people, func(p *TestType) int { return p.ID })
+	assert.True(t, goset.NewSet(1, 2, 3).Equal(ids))
+
+	lengths := goset.Map(goset.NewSet("a", "bb", "cc", "ddd"), func(s string) int { return len(s) })
+	assert.True(t, goset.NewSet(1, 2, 3).Equal(lengths))
+
+	assert.Zero(t, goset.Map(goset.NewSet[string](), func(s string) int { return len(s) }).Len())
+}
+
+func TestIntersectPredicate(t *testing.T) {
+	isPrime := func(v int) bool {
+		if v < 2 {
+			return false
+		}
+		for d := 2; d*d <= v; d++ {
+			if v%d == 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	set := goset.NewSet(0, 1, 2, 3, 4, 5, 9, 11, 15, 17)
+	assert.True(t, goset.NewSet(2, 3, 5, 11, 17).Equal(goset.IntersectPredicate(set, isPrime)))
+	assert.Equal(t, 10, set.Len())
+	assert.Zero(t, goset.IntersectPredicate(goset.NewSet(4, 6, 8), isPrime).Len())
+}
@@ -1,7 +1,12 @@
 package goset
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"iter"
+	"reflect"
+	"slices"
 	"strings"
 )
 
@@ -9,10 +14,13 @@ type unsafeResolvingSet[T any, U comparable] struct {
 	set       map[U]T
 	keyGetter KeyGetter[T, U]
 	resolver  Resolver[T]
+	// less orders the elements of a priority set, whose resolver keeps the greater of two elements under it.
+	// It is nil for other resolving sets, since a resolver in general is not an ordering.
+	less func(a, b T) bool
 }
 
-// Assert concrete type:unsafeResolvingSet adheres to Set interface.
-var _ Set[int] = (*unsafeResolvingSet[int, string])(nil)
+// Assert concrete type:unsafeResolvingSet adheres to ResolvingSet interface.
+var _ ResolvingSet[int, string] = (*unsafeResolvingSet[int, string])(nil)
 
 func newUnsafeResolvingSet[T any, U comparable](keyGetter KeyGetter[T, U], resolver Resolver[T]) *unsafeResolvingSet[T, U] {
 	return &unsafeResolvingSet[T, U]{
@@ -22,6 +30,19 @@ func newUnsafeResolvingSet[T any, U comparable](keyGetter KeyGetter[T, U], resol
 	}
 }
 
+func newUnsafePrioritySet[T any, U comparable](keyGetter KeyGetter[T, U], less func(a, b T) bool) *unsafeResolvingSet[T, U] {
+	set := newUnsafeResolvingSet(keyGetter, MaxResolver(less))
+	set.less = less
+	return set
+}
+
+// empty returns an empty set with the same keying, resolver and ordering as s.
+func (s *unsafeResolvingSet[T, U]) empty() *unsafeResolvingSet[T, U] {
+	set := newUnsafeResolvingSet(s.keyGetter, s.resolver)
+	set.less = s.less
+	return set
+}
+
 func (s *unsafeResolvingSet[T, U]) Add(v ...T) bool {
 	var ret bool
 	for _, val := range v {
@@ -43,22 +64,113 @@ func (s *unsafeResolvingSet[T, U]) Add(v ...T) bool {
 	return ret
 }
 
+func (s *unsafeResolvingSet[T, U]) AddSet(other Set[T]) bool {
+	var ret bool
+	other.Each(func(elem T) bool {
+		if s.Add(elem) {
+			ret = true
+		}
+		return true
+	})
+	return ret
+}
+
+func (s *unsafeResolvingSet[T, U]) WouldAdd(v T) bool {
+	foundItem, ok := s.set[s.keyGetter(v)]
+	if !ok {
+		return true
+	}
+	if s.resolver == nil {
+		return false
+	}
+	_, replace := s.resolver(foundItem, v)
+	return replace
+}
+
+func (s *unsafeResolvingSet[T, U]) Update(v T) bool {
+	key := s.keyGetter(v)
+	_, ok := s.set[key]
+	s.set[key] = v
+	return ok
+}
+
+func (s *unsafeResolvingSet[T, U]) Upsert(v T) (T, bool) {
+	stored, _, _, replaced := s.upsert(v)
+	return stored, replaced
+}
+
+// upsert is Upsert that also returns the element previously stored under the key of v, if existed.
+func (s *unsafeResolvingSet[T, U]) upsert(v T) (stored T, previous T, existed bool, replaced bool) {
+	key := s.keyGetter(v)
+	foundItem, ok := s.set[key]
+	if !ok {
+		s.set[key] = v
+		return v, previous, false, false
+	}
+	if s.resolver == nil {
+		return foundItem, foundItem, true, false
+	}
+	newItem, replace := s.resolver(foundItem, v)
+	if !replace {
+		return foundItem, foundItem, true, false
+	}
+	s.set[key] = newItem
+	return newItem, foundItem, true, true
+}
+
 func (s *unsafeResolvingSet[T, U]) Len() int {
 	return len(s.set)
 }
 
+func (s *unsafeResolvingSet[T, U]) IsEmpty() bool {
+	return len(s.set) == 0
+}
+
+func (s *unsafeResolvingSet[T, U]) Stats() SetStats {
+	return SetStats{Len: s.Len(), IsEmpty: s.IsEmpty(), MapBacked: true}
+}
+
 func (s *unsafeResolvingSet[T, U]) Clear() {
 	s.set = make(map[U]T)
 }
 
 func (s *unsafeResolvingSet[T, U]) Clone() Set[T] {
-	clonedSet := newUnsafeResolvingSet(s.keyGetter, s.resolver)
+	return s.CloneTyped()
+}
+
+func (s *unsafeResolvingSet[T, U]) CloneTyped() ResolvingSet[T, U] {
+	return s.clone()
+}
+
+func (s *unsafeResolvingSet[T, U]) clone() *unsafeResolvingSet[T, U] {
+	clonedSet := s.empty()
 	for _, elem := range s.set {
 		clonedSet.Add(elem)
 	}
 	return clonedSet
 }
 
+func (s *unsafeResolvingSet[T, U]) threadSafe() Set[T] {
+	return wrapResolving(s)
+}
+
+func (s *unsafeResolvingSet[T, U]) KeyFunc() KeyGetter[T, U] {
+	return s.keyGetter
+}
+
+func (s *unsafeResolvingSet[T, U]) ResolverFunc() Resolver[T] {
+	return s.resolver
+}
+
+func (s *unsafeResolvingSet[T, U]) CloneWith(copy func(T) T) Set[T] {
+	clonedSet := s.empty()
+	for _, elem := range s.set {
+		copied := copy(elem)
+		clonedSet.set[s.keyGetter(copied)] = copied
+	}
+	return clonedSet
+}
+
 func (s *unsafeResolvingSet[T, U]) contains(v T) bool {
 	key := s.keyGetter(v)
 	_, ok := s.set[key]
@@ -78,9 +190,50 @@ func (s *unsafeResolvingSet[T, U]) Contains(v ...T) bool {
 	return true
 }
 
+func (s *unsafeResolvingSet[T, U]) EachSnapshot(fn func(T) bool) {
+	eachOf(s.ToSlice(), fn)
+}
+
+func (s *unsafeResolvingSet[T, U]) Filter(pred func(T) bool) Set[T] {
+	filtered := s.empty()
+	for key, elem := range s.set {
+		if pred(elem) {
+			filtered.set[key] = elem
+		}
+	}
+	return filtered
+}
+
+func (s *unsafeResolvingSet[T, U]) Partition(pred func(T) bool) (Set[T], Set[T]) {
+	matching := s.empty()
+	rest := s.empty()
+	for key, elem := range s.set {
+		if pred(elem) {
+			matching.set[key] = elem
+		} else {
+			rest.set[key] = elem
+		}
+	}
+	return matching, rest
+}
+
+func (s *unsafeResolvingSet[T, U]) Any(pred func(T) bool) bool {
+	return anyOf(s.Each, pred)
+}
+
+func (s *unsafeResolvingSet[T, U]) All(pred func(T) bool) bool {
+	return allOf(s.Each, pred)
+}
+
 func (s *unsafeResolvingSet[T, U]) Diff(other Set[T]) Set[T] {
-	o := other.(*unsafeResolvingSet[T, U])
-	diff := newUnsafeResolvingSet(s.keyGetter, s.resolver)
+	if other.IsEmpty() {
+		return s.Clone()
+	}
+	o, ok := other.(*unsafeResolvingSet[T, U])
+	if !ok {
+		return diffByContains[T](s.empty(), s, other)
+	}
+	diff := s.empty()
 	for _, elem := range s.set {
 		if !o.contains(elem) {
 			diff.Add(elem)
@@ -89,8 +242,25 @@ func (s *unsafeResolvingSet[T, U]) Diff(other Set[T]) Set[T] {
 	return diff
 }
 
+func (s *unsafeResolvingSet[T, U]) DiffLen(other Set[T]) int {
+	o, ok := other.(*unsafeResolvingSet[T, U])
+	if !ok {
+		return diffLenByContains[T](s, other)
+	}
+	count := 0
+	for key := range s.set {
+		if _, ok := o.set[key]; !ok {
+			count++
+		}
+	}
+	return count
+}
+
 func (s *unsafeResolvingSet[T, U]) SymmetricDiff(other Set[T]) Set[T] {
-	o := other.(*unsafeResolvingSet[T, U])
+	o, ok := other.(*unsafeResolvingSet[T, U])
+	if !ok {
+		return symmetricDiffByContains[T](s.empty(), s, other)
+	}
 	diff := o.Diff(s)
 	for _, elem := range s.set {
 		if !o.contains(elem) {
@@ -100,6 +270,15 @@ func (s *unsafeResolvingSet[T, U]) SymmetricDiff(other Set[T]) Set[T] {
 	return diff
 }
 
+func (s *unsafeResolvingSet[T, U]) ContainsAny(v ...T) bool {
+	for _, val := range v {
+		if s.contains(val) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *unsafeResolvingSet[T, U]) Each(fn func(T) bool) {
 	for _, elem := range s.set {
 		if !fn(elem) {
@@ -108,22 +287,57 @@ func (s *unsafeResolvingSet[T, U]) Each(fn func(T) bool) {
 	}
 }
 
+func (s *unsafeResolvingSet[T, U]) EachMutable(fn func(T) (keep bool)) {
+	for key, elem := range s.set {
+		if !fn(elem) {
+			delete(s.set, key)
+		}
+	}
+}
+
 func (s *unsafeResolvingSet[T, U]) Equal(other Set[T]) bool {
-	o := other.(*unsafeResolvingSet[T, U])
+	o, ok := other.(*unsafeResolvingSet[T, U])
+	if !ok {
+		if safe, ok := other.(lockableSet[T]); ok {
+			// let the thread-safe set lock itself and compare its resolving set with this one
+			return safe.Equal(s)
+		}
+		return equalByContains[T](s, other)
+	}
 	if s.Len() != other.Len() {
 		return false
 	}
-	for _, elem := range s.set {
-		if !o.contains(elem) {
+	for key, elem := range s.set {
+		otherElem, ok := o.set[key]
+		if !ok || !sameValue(s, o, elem, otherElem) {
 			return false
 		}
 	}
 	return true
 }
 
+// sameValue returns a boolean indicating if a, stored in s, and b, stored in o under the same key, are equal.
+// Elements of priority sets are equal when neither is less than the other under the ordering of either set.
+// Other resolvers do not order elements, so the elements are compared with reflect.DeepEqual instead.
+func sameValue[T any, U comparable](s, o *unsafeResolvingSet[T, U], a, b T) bool {
+	if s.less == nil && o.less == nil {
+		return reflect.DeepEqual(a, b)
+	}
+	equivalent := func(less func(a, b T) bool) bool {
+		return less == nil || (!less(a, b) && !less(b, a))
+	}
+	return equivalent(s.less) && equivalent(o.less)
+}
+
 func (s *unsafeResolvingSet[T, U]) Intersect(other Set[T]) Set[T] {
-	o := other.(*unsafeResolvingSet[T, U])
-	intersection := newUnsafeResolvingSet(s.keyGetter, s.resolver)
+	if other.IsEmpty() {
+		return s.empty()
+	}
+	o, ok := other.(*unsafeResolvingSet[T, U])
+	if !ok {
+		return intersectByContains[T](s.empty(), s, other)
+	}
+	intersection := s.empty()
 
 	smallerSet := s
 	largerSet := o
@@ -141,8 +355,54 @@ func (s *unsafeResolvingSet[T, U]) Intersect(other Set[T]) Set[T] {
 	return intersection
 }
 
+func (s *unsafeResolvingSet[T, U]) IntersectLen(other Set[T]) int {
+	o, ok := other.(*unsafeResolvingSet[T, U])
+	if !ok {
+		return intersectLenByContains[T](s, other)
+	}
+	smallerSet, largerSet := s, o
+	if o.Len() < s.Len() {
+		smallerSet, largerSet = o, s
+	}
+	count := 0
+	for key := range smallerSet.set {
+		if _, ok := largerSet.set[key]; ok {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *unsafeResolvingSet[T, U]) Disjoint(other Set[T]) bool {
+	o, ok := other.(*unsafeResolvingSet[T, U])
+	if !ok {
+		return disjointByContains[T](s, other)
+	}
+	smallerSet, largerSet := s, o
+	if o.Len() < s.Len() {
+		smallerSet, largerSet = o, s
+	}
+	for key := range smallerSet.set {
+		if _, ok := largerSet.set[key]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *unsafeResolvingSet[T, U]) IntersectWith(other Set[T]) {
+	for key, elem := range s.set {
+		if !other.Contains(elem) {
+			delete(s.set, key)
+		}
+	}
+}
+
 func (s *unsafeResolvingSet[T, U]) IsSubset(other Set[T]) bool {
-	o := other.(*unsafeResolvingSet[T, U])
+	o, ok := other.(*unsafeResolvingSet[T, U])
+	if !ok {
+		return isSubsetByContains[T](s, other)
+	}
 	if s.Len() > other.Len() {
 		return false
 	}
@@ -166,15 +426,29 @@ func (s *unsafeResolvingSet[T, U]) IsProperSuperset(other Set[T]) bool {
 }
 
 func (s *unsafeResolvingSet[T, U]) Iter() <-chan T {
-	ch := make(chan T, s.Len())
+	return sliceChan(s.ToSlice())
+}
 
-	go func() {
-		defer close(ch)
-		for _, elem := range s.set {
-			ch <- elem
-		}
-	}()
-	return ch
+func (s *unsafeResolvingSet[T, U]) IterContext(ctx context.Context) <-chan T {
+	return iterContext(ctx, s.Len(), s.Each)
+}
+
+func (s *unsafeResolvingSet[T, U]) Iterator() iter.Seq[T] {
+	return s.Each
+}
+
+func (s *unsafeResolvingSet[T, U]) Consume() <-chan T {
+	elems := s.ToSlice()
+	s.Clear()
+	return sliceChan(elems)
+}
+
+func (s *unsafeResolvingSet[T, U]) Sample() (T, bool) {
+	return sampleOf(s.Each)
+}
+
+func (s *unsafeResolvingSet[T, U]) SampleN(n int) []T {
+	return sampleNOf(s.Len(), s.Each, n)
 }
 
 func (s *unsafeResolvingSet[T, U]) Remove(v ...T) {
@@ -184,13 +458,115 @@ func (s *unsafeResolvingSet[T, U]) Remove(v ...T) {
 	}
 }
 
+func (s *unsafeResolvingSet[T, U]) RemoveSet(other Set[T]) {
+	other.Each(func(elem T) bool {
+		delete(s.set, s.keyGetter(elem))
+		return true
+	})
+}
+
+func (s *unsafeResolvingSet[T, U]) RemoveIf(pred func(T) bool) int {
+	return removeIfByEachMutable[T](s, pred)
+}
+
+func (s *unsafeResolvingSet[T, U]) ReplaceAll(items []T) {
+	s.set = make(map[U]T, len(items))
+	s.Add(items...)
+}
+
+func (s *unsafeResolvingSet[T, U]) Toggle(v ...T) int {
+	prevLen := s.Len()
+	for _, val := range v {
+		key := s.keyGetter(val)
+		if _, ok := s.set[key]; ok {
+			delete(s.set, key)
+		} else {
+			s.set[key] = val
+		}
+	}
+	return s.Len() - prevLen
+}
+
+func (s *unsafeResolvingSet[T, U]) RemoveReturning(v ...T) []T {
+	var removed []T
+	for _, val := range v {
+		key := s.keyGetter(val)
+		if elem, ok := s.set[key]; ok {
+			delete(s.set, key)
+			removed = append(removed, elem)
+		}
+	}
+	return removed
+}
+
+func (s *unsafeResolvingSet[T, U]) Get(key U) (T, bool) {
+	elem, ok := s.set[key]
+	return elem, ok
+}
+
+func (s *unsafeResolvingSet[T, U]) RemoveKey(keys ...U) {
+	for _, key := range keys {
+		delete(s.set, key)
+	}
+}
+
 func (s *unsafeResolvingSet[T, U]) Pop() (T, bool) {
-	for _, elem := range s.set {
-		s.Remove(elem)
-		return elem, true
+	var best T
+	var bestKey U
+	found := false
+	for key, elem := range s.set {
+		if !found {
+			best, bestKey, found = elem, key, true
+			if s.less == nil {
+				break
+			}
+			continue
+		}
+		if s.less(best, elem) {
+			best, bestKey = elem, key
+		}
 	}
-	var zeroElem T
-	return zeroElem, false
+	if found {
+		delete(s.set, bestKey)
+	}
+	return best, found
+}
+
+// PopN removes n elements in the order repeated calls to Pop would, sorting the elements of a priority set once
+// rather than scanning the set for every element.
+func (s *unsafeResolvingSet[T, U]) PopN(n int) []T {
+	n = max(min(n, s.Len()), 0)
+	type entry struct {
+		key  U
+		elem T
+	}
+	entries := make([]entry, 0, len(s.set))
+	for key, elem := range s.set {
+		if s.less == nil && len(entries) == n {
+			break
+		}
+		entries = append(entries, entry{key: key, elem: elem})
+	}
+	if s.less != nil {
+		// greatest first, as Pop removes them
+		slices.SortFunc(entries, func(a, b entry) int {
+			switch {
+			case s.less(b.elem, a.elem):
+				return -1
+			case s.less(a.elem, b.elem):
+				return 1
+			default:
+				return 0
+			}
+		})
+	}
+
+	popped := make([]T, n)
+	for i, e := range entries[:n] {
+		popped[i] = e.elem
+		delete(s.set, e.key)
+	}
+	return popped
 }
 
 func (s *unsafeResolvingSet[T, U]) String() string {
@@ -202,8 +578,14 @@ func (s *unsafeResolvingSet[T, U]) String() string {
 }
 
 func (s *unsafeResolvingSet[T, U]) Union(other Set[T]) Set[T] {
-	o := other.(*unsafeResolvingSet[T, U])
-	union := newUnsafeResolvingSet(s.keyGetter, s.resolver)
+	if other.IsEmpty() {
+		return s.Clone()
+	}
+	o, ok := other.(*unsafeResolvingSet[T, U])
+	if !ok {
+		return unionByContains[T](s.empty(), s, other)
+	}
+	union := s.empty()
 
 	for _, elem := range s.set {
 		union.Add(elem)
@@ -213,6 +595,10 @@ func (s *unsafeResolvingSet[T, U]) Union(other Set[T]) Set[T] {
 	}
 	return union
 }
+func (s *unsafeResolvingSet[T, U]) UnionWith(other Set[T]) bool {
+	return s.AddSet(other)
+}
+
 func (s *unsafeResolvingSet[T, U]) ToSlice() []T {
 	var elems []T
 	for _, elem := range s.set {
@@ -220,3 +606,27 @@ func (s *unsafeResolvingSet[T, U]) ToSlice() []T {
 	}
 	return elems
 }
+
+func (s *unsafeResolvingSet[T, U]) ToSortedSlice(less func(a, b T) bool) []T {
+	return sortedSlice(s.AppendTo(make([]T, 0, s.Len())), less)
+}
+
+func (s *unsafeResolvingSet[T, U]) AppendTo(dst []T) []T {
+	for _, elem := range s.set {
+		dst = append(dst, elem)
+	}
+	return dst
+}
+
+func (s *unsafeResolvingSet[T, U]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.AppendTo(make([]T, 0, s.Len())))
+}
+
+func (s *unsafeResolvingSet[T, U]) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalJSONElems[T](data)
+	if err != nil {
+		return err
+	}
+	s.Add(elems...)
+	return nil
+}
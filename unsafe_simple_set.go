@@ -1,7 +1,10 @@
 package goset
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"iter"
 	"strings"
 )
 
@@ -15,6 +18,11 @@ func newUnsafeSimpleSet[T comparable]() *unsafeSimpleSet[T] {
 	return &set
 }
 
+func newUnsafeSimpleSetWithCapacity[T comparable](capacity int) *unsafeSimpleSet[T] {
+	set := make(unsafeSimpleSet[T], max(capacity, 0))
+	return &set
+}
+
 func (s *unsafeSimpleSet[T]) add(v ...T) {
 	for _, val := range v {
 		(*s)[val] = struct{}{}
@@ -27,10 +35,31 @@ func (s *unsafeSimpleSet[T]) Add(v ...T) bool {
 	return prevLen != s.Len()
 }
 
+func (s *unsafeSimpleSet[T]) AddSet(other Set[T]) bool {
+	prevLen := s.Len()
+	other.Each(func(elem T) bool {
+		s.add(elem)
+		return true
+	})
+	return prevLen != s.Len()
+}
+
+func (s *unsafeSimpleSet[T]) WouldAdd(v T) bool {
+	return !s.contains(v)
+}
+
 func (s *unsafeSimpleSet[T]) Len() int {
 	return len(*s)
 }
 
+func (s *unsafeSimpleSet[T]) IsEmpty() bool {
+	return len(*s) == 0
+}
+
+func (s *unsafeSimpleSet[T]) Stats() SetStats {
+	return SetStats{Len: s.Len(), IsEmpty: s.IsEmpty(), MapBacked: true}
+}
+
 func (s *unsafeSimpleSet[T]) Clear() {
 	*s = make(unsafeSimpleSet[T])
 }
@@ -43,6 +72,14 @@ func (s *unsafeSimpleSet[T]) Clone() Set[T] {
 	return &clone
 }
 
+func (s *unsafeSimpleSet[T]) CloneWith(copy func(T) T) Set[T] {
+	clone := make(unsafeSimpleSet[T], s.Len())
+	for elem := range *s {
+		clone.add(copy(elem))
+	}
+	return &clone
+}
+
 func (s *unsafeSimpleSet[T]) contains(v T) bool {
 	_, ok := (*s)[v]
 	return ok
@@ -57,6 +94,15 @@ func (s *unsafeSimpleSet[T]) Contains(v ...T) bool {
 	return true
 }
 
+func (s *unsafeSimpleSet[T]) ContainsAny(v ...T) bool {
+	for _, val := range v {
+		if s.contains(val) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *unsafeSimpleSet[T]) Each(fn func(T) bool) {
 	for elem := range *s {
 		if !fn(elem) {
@@ -65,8 +111,56 @@ func (s *unsafeSimpleSet[T]) Each(fn func(T) bool) {
 	}
 }
 
+func (s *unsafeSimpleSet[T]) EachMutable(fn func(T) (keep bool)) {
+	for elem := range *s {
+		if !fn(elem) {
+			delete(*s, elem)
+		}
+	}
+}
+
+func (s *unsafeSimpleSet[T]) EachSnapshot(fn func(T) bool) {
+	eachOf(s.ToSlice(), fn)
+}
+
+func (s *unsafeSimpleSet[T]) Filter(pred func(T) bool) Set[T] {
+	filtered := newUnsafeSimpleSet[T]()
+	for elem := range *s {
+		if pred(elem) {
+			filtered.add(elem)
+		}
+	}
+	return filtered
+}
+
+func (s *unsafeSimpleSet[T]) Partition(pred func(T) bool) (Set[T], Set[T]) {
+	matching, rest := newUnsafeSimpleSet[T](), newUnsafeSimpleSet[T]()
+	for elem := range *s {
+		if pred(elem) {
+			matching.add(elem)
+		} else {
+			rest.add(elem)
+		}
+	}
+	return matching, rest
+}
+
+func (s *unsafeSimpleSet[T]) Any(pred func(T) bool) bool {
+	return anyOf(s.Each, pred)
+}
+
+func (s *unsafeSimpleSet[T]) All(pred func(T) bool) bool {
+	return allOf(s.Each, pred)
+}
+
 func (s *unsafeSimpleSet[T]) Diff(other Set[T]) Set[T] {
-	o := other.(*unsafeSimpleSet[T])
+	if other.IsEmpty() {
+		return s.Clone()
+	}
+	o, ok := other.(*unsafeSimpleSet[T])
+	if !ok {
+		return diffByContains[T](newUnsafeSimpleSet[T](), s, other)
+	}
 	diff := newUnsafeSimpleSet[T]()
 	for elem := range *s {
 		if !o.contains(elem) {
@@ -76,8 +170,25 @@ func (s *unsafeSimpleSet[T]) Diff(other Set[T]) Set[T] {
 	return diff
 }
 
+func (s *unsafeSimpleSet[T]) DiffLen(other Set[T]) int {
+	o, ok := other.(*unsafeSimpleSet[T])
+	if !ok {
+		return diffLenByContains[T](s, other)
+	}
+	count := 0
+	for elem := range *s {
+		if !o.contains(elem) {
+			count++
+		}
+	}
+	return count
+}
+
 func (s *unsafeSimpleSet[T]) SymmetricDiff(other Set[T]) Set[T] {
-	o := other.(*unsafeSimpleSet[T])
+	o, ok := other.(*unsafeSimpleSet[T])
+	if !ok {
+		return symmetricDiffByContains[T](newUnsafeSimpleSet[T](), s, other)
+	}
 	diff := o.Diff(s)
 	for elem := range *s {
 		if !o.contains(elem) {
@@ -88,7 +199,10 @@ func (s *unsafeSimpleSet[T]) SymmetricDiff(other Set[T]) Set[T] {
 }
 
 func (s *unsafeSimpleSet[T]) Equal(other Set[T]) bool {
-	o := other.(*unsafeSimpleSet[T])
+	o, ok := other.(*unsafeSimpleSet[T])
+	if !ok {
+		return equalByContains[T](s, other)
+	}
 	if s.Len() != other.Len() {
 		return false
 	}
@@ -101,7 +215,13 @@ func (s *unsafeSimpleSet[T]) Equal(other Set[T]) bool {
 }
 
 func (s *unsafeSimpleSet[T]) Intersect(other Set[T]) Set[T] {
-	o := other.(*unsafeSimpleSet[T])
+	if other.IsEmpty() {
+		return newUnsafeSimpleSet[T]()
+	}
+	o, ok := other.(*unsafeSimpleSet[T])
+	if !ok {
+		return intersectByContains[T](newUnsafeSimpleSet[T](), s, other)
+	}
 	intersection := newUnsafeSimpleSet[T]()
 
 	smallerSet := s
@@ -119,8 +239,54 @@ func (s *unsafeSimpleSet[T]) Intersect(other Set[T]) Set[T] {
 	return intersection
 }
 
+func (s *unsafeSimpleSet[T]) IntersectLen(other Set[T]) int {
+	o, ok := other.(*unsafeSimpleSet[T])
+	if !ok {
+		return intersectLenByContains[T](s, other)
+	}
+	smallerSet, largerSet := s, o
+	if o.Len() < s.Len() {
+		smallerSet, largerSet = o, s
+	}
+	count := 0
+	for elem := range *smallerSet {
+		if largerSet.contains(elem) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *unsafeSimpleSet[T]) Disjoint(other Set[T]) bool {
+	o, ok := other.(*unsafeSimpleSet[T])
+	if !ok {
+		return disjointByContains[T](s, other)
+	}
+	smallerSet, largerSet := s, o
+	if o.Len() < s.Len() {
+		smallerSet, largerSet = o, s
+	}
+	for elem := range *smallerSet {
+		if largerSet.contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *unsafeSimpleSet[T]) IntersectWith(other Set[T]) {
+	for elem := range *s {
+		if !other.Contains(elem) {
+			delete(*s, elem)
+		}
+	}
+}
+
 func (s *unsafeSimpleSet[T]) IsSubset(other Set[T]) bool {
-	o := other.(*unsafeSimpleSet[T])
+	o, ok := other.(*unsafeSimpleSet[T])
+	if !ok {
+		return isSubsetByContains[T](s, other)
+	}
 	if s.Len() > other.Len() {
 		return false
 	}
@@ -145,15 +311,21 @@ func (s *unsafeSimpleSet[T]) IsProperSuperset(other Set[T]) bool {
 }
 
 func (s *unsafeSimpleSet[T]) Iter() <-chan T {
-	ch := make(chan T, s.Len())
+	return sliceChan(s.ToSlice())
+}
 
-	go func() {
-		defer close(ch)
-		for elem := range *s {
-			ch <- elem
-		}
-	}()
-	return ch
+func (s *unsafeSimpleSet[T]) IterContext(ctx context.Context) <-chan T {
+	return iterContext(ctx, s.Len(), s.Each)
+}
+
+func (s *unsafeSimpleSet[T]) Iterator() iter.Seq[T] {
+	return s.Each
+}
+
+func (s *unsafeSimpleSet[T]) Consume() <-chan T {
+	elems := s.ToSlice()
+	s.Clear()
+	return sliceChan(elems)
 }
 
 func (s *unsafeSimpleSet[T]) Pop() (T, bool) {
@@ -165,14 +337,68 @@ func (s *unsafeSimpleSet[T]) Pop() (T, bool) {
 	return zeroElem, false
 }
 
+func (s *unsafeSimpleSet[T]) PopN(n int) []T {
+	popped := make([]T, 0, max(min(n, s.Len()), 0))
+	for elem := range *s {
+		if len(popped) >= n {
+			break
+		}
+		delete(*s, elem)
+		popped = append(popped, elem)
+	}
+	return popped
+}
+
+func (s *unsafeSimpleSet[T]) Sample() (T, bool) {
+	return sampleOf(s.Each)
+}
+
+func (s *unsafeSimpleSet[T]) SampleN(n int) []T {
+	return sampleNOf(s.Len(), s.Each, n)
+}
+
 func (s *unsafeSimpleSet[T]) Remove(v ...T) {
 	for _, val := range v {
 		delete(*s, val)
 	}
 }
 
+func (s *unsafeSimpleSet[T]) RemoveSet(other Set[T]) {
+	other.Each(func(elem T) bool {
+		delete(*s, elem)
+		return true
+	})
+}
+
+func (s *unsafeSimpleSet[T]) RemoveIf(pred func(T) bool) int {
+	return removeIfByEachMutable[T](s, pred)
+}
+
+func (s *unsafeSimpleSet[T]) ReplaceAll(items []T) {
+	*s = make(unsafeSimpleSet[T], len(items))
+	s.add(items...)
+}
+
+func (s *unsafeSimpleSet[T]) Toggle(v ...T) int {
+	prevLen := s.Len()
+	for _, val := range v {
+		if s.contains(val) {
+			delete(*s, val)
+		} else {
+			s.add(val)
+		}
+	}
+	return s.Len() - prevLen
+}
+
 func (s *unsafeSimpleSet[T]) Union(other Set[T]) Set[T] {
-	o := other.(*unsafeSimpleSet[T])
+	if other.IsEmpty() {
+		return s.Clone()
+	}
+	o, ok := other.(*unsafeSimpleSet[T])
+	if !ok {
+		return unionByContains[T](newUnsafeSimpleSet[T](), s, other)
+	}
 	union := newUnsafeSimpleSet[T]()
 
 	for elem := range *s {
@@ -184,6 +410,10 @@ func (s *unsafeSimpleSet[T]) Union(other Set[T]) Set[T] {
 	return union
 }
 
+func (s *unsafeSimpleSet[T]) UnionWith(other Set[T]) bool {
+	return s.AddSet(other)
+}
+
 func (s *unsafeSimpleSet[T]) ToSlice() []T {
 	var elems []T
 	for elem := range *s {
@@ -192,6 +422,17 @@ func (s *unsafeSimpleSet[T]) ToSlice() []T {
 	return elems
 }
 
+func (s *unsafeSimpleSet[T]) ToSortedSlice(less func(a, b T) bool) []T {
+	return sortedSlice(s.AppendTo(make([]T, 0, s.Len())), less)
+}
+
+func (s *unsafeSimpleSet[T]) AppendTo(dst []T) []T {
+	for elem := range *s {
+		dst = append(dst, elem)
+	}
+	return dst
+}
+
 func (s *unsafeSimpleSet[T]) String() string {
 	var items []string
 	for elem := range *s {
@@ -199,3 +440,47 @@ func (s *unsafeSimpleSet[T]) String() string {
 	}
 	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
 }
+
+func (s *unsafeSimpleSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.AppendTo(make([]T, 0, s.Len())))
+}
+
+func (s *unsafeSimpleSet[T]) UnmarshalJSON(data []byte) error {
+	elems, err := unmarshalJSONElems[T](data)
+	if err != nil {
+		return err
+	}
+	if *s == nil {
+		*s = make(unsafeSimpleSet[T], len(elems))
+	}
+	s.add(elems...)
+	return nil
+}
+
+func (s *unsafeSimpleSet[T]) GobEncode() ([]byte, error) {
+	return gobEncodeElems(s.AppendTo(make([]T, 0, s.Len())))
+}
+
+func (s *unsafeSimpleSet[T]) GobDecode(data []byte) error {
+	elems, err := gobDecodeElems[T](data)
+	if err != nil {
+		return err
+	}
+	*s = make(unsafeSimpleSet[T], len(elems))
+	s.add(elems...)
+	return nil
+}
+
+func (s *unsafeSimpleSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinaryElems(s.AppendTo(make([]T, 0, s.Len())))
+}
+
+func (s *unsafeSimpleSet[T]) UnmarshalBinary(data []byte) error {
+	elems, err := unmarshalBinaryElems[T](data)
+	if err != nil {
+		return err
+	}
+	*s = make(unsafeSimpleSet[T], len(elems))
+	s.add(elems...)
+	return nil
+}
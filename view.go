@@ -0,0 +1,59 @@
+package goset
+
+type view[T any] struct {
+	set  Set[T]
+	pred func(T) bool
+}
+
+// Assert concrete type:view adheres to ReadOnlySet interface.
+var _ ReadOnlySet[int] = (*view[int])(nil)
+
+// View returns a read-only view of the elements in s that satisfy pred. The view does not copy s:
+// every operation applies pred on the fly, so the view reflects later changes to s.
+// Len is O(n) for a view, since it has to count the matching elements.
+func View[T any](s Set[T], pred func(T) bool) ReadOnlySet[T] {
+	return &view[T]{
+		set:  s,
+		pred: pred,
+	}
+}
+
+func (v *view[T]) Len() int {
+	count := 0
+	v.Each(func(T) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+func (v *view[T]) Contains(items ...T) bool {
+	for _, item := range items {
+		if !v.pred(item) || !v.set.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *view[T]) Each(fn func(T) bool) {
+	v.set.Each(func(elem T) bool {
+		if !v.pred(elem) {
+			return true
+		}
+		return fn(elem)
+	})
+}
+
+func (v *view[T]) Iter() <-chan T {
+	return sliceChan(v.ToSlice())
+}
+
+func (v *view[T]) ToSlice() []T {
+	var elems []T
+	v.Each(func(elem T) bool {
+		elems = append(elems, elem)
+		return true
+	})
+	return elems
+}
@@ -0,0 +1,45 @@
+package goset_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestView(t *testing.T) {
+	set := goset.NewSet(1, 2, 3, 4, 5, 6)
+	even := goset.View(set, func(v int) bool { return v%2 == 0 })
+
+	assert.Equal(t, 3, even.Len())
+	assert.True(t, even.Contains(2, 4))
+	assert.False(t, even.Contains(3))
+	assert.False(t, even.Contains(8))
+
+	actualItems := even.ToSlice()
+	sort.Ints(actualItems)
+	assert.EqualValues(t, []int{2, 4, 6}, actualItems)
+
+	set.Add(8, 9)
+	set.Remove(2)
+	assert.Equal(t, 3, even.Len())
+	assert.True(t, even.Contains(8))
+	assert.False(t, even.Contains(2))
+
+	var iterItems []int
+	for v := range even.Iter() {
+		iterItems = append(iterItems, v)
+	}
+	sort.Ints(iterItems)
+	assert.EqualValues(t, []int{4, 6, 8}, iterItems)
+
+	count := 0
+	even.Each(func(v int) bool {
+		assert.Zero(t, v%2)
+		count++
+		return count < 2
+	})
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,130 @@
+package goset
+
+import (
+	"sync"
+	"weak"
+)
+
+// WeakSet is a thread-safe set of pointers that does not keep its elements alive.
+// Elements are identified by pointer identity, or by a key when created by NewWeakSetWithKey. Once the garbage
+// collector has reclaimed an element it is skipped by Contains, Each, Iter and ToSlice, but it still counts towards
+// Len until Prune is called. How soon an element disappears depends entirely on when the garbage collector runs.
+type WeakSet[T any] struct {
+	mu  sync.RWMutex
+	key func(*T) any
+	set map[any]weak.Pointer[T]
+}
+
+// NewWeakSet returns an empty WeakSet whose elements are identified by pointer identity.
+func NewWeakSet[T any]() *WeakSet[T] {
+	return &WeakSet[T]{
+		key: func(v *T) any { return weak.Make(v) },
+		set: make(map[any]weak.Pointer[T]),
+	}
+}
+
+// NewWeakSetWithKey returns an empty WeakSet whose elements are unique by the key returned by keyGetter, so that
+// distinct pointers to equivalent values are one element. The first element added for a key is kept until
+// it is removed or garbage collected. The key of an element is computed when it is added and must not change.
+func NewWeakSetWithKey[T any, U comparable](keyGetter KeyGetter[*T, U]) *WeakSet[T] {
+	return &WeakSet[T]{
+		key: func(v *T) any { return keyGetter(v) },
+		set: make(map[any]weak.Pointer[T]),
+	}
+}
+
+// Add adds one or more elements to the set and returns a boolean indicating if the set changed.
+// Nil elements are ignored.
+func (s *WeakSet[T]) Add(v ...*T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := false
+	for _, val := range v {
+		if val == nil {
+			continue
+		}
+		key := s.key(val)
+		// an entry whose element has been collected is replaced like a missing one
+		if ptr, ok := s.set[key]; ok && ptr.Value() != nil {
+			continue
+		}
+		s.set[key] = weak.Make(val)
+		changed = true
+	}
+	return changed
+}
+
+// Remove removes the given elements from the set.
+func (s *WeakSet[T]) Remove(v ...*T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, val := range v {
+		if val != nil {
+			delete(s.set, s.key(val))
+		}
+	}
+}
+
+// Contains returns a boolean indicating if all the given elements are in the set.
+func (s *WeakSet[T]) Contains(v ...*T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, val := range v {
+		if val == nil {
+			return false
+		}
+		if ptr, ok := s.set[s.key(val)]; !ok || ptr.Value() == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of entries in the set, including elements that have been
+// garbage collected since the last call to Prune.
+func (s *WeakSet[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.set)
+}
+
+// Each iterates over the live elements in the set applying the given function on each element.
+// Breaks iteration if the given function returns false
+func (s *WeakSet[T]) Each(fn func(*T) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, ptr := range s.set {
+		if elem := ptr.Value(); elem != nil && !fn(elem) {
+			break
+		}
+	}
+}
+
+// Iter returns a channel of all the live elements in the set which allows the caller to range over the elements
+func (s *WeakSet[T]) Iter() <-chan *T {
+	return sliceChan(s.ToSlice())
+}
+
+// ToSlice returns a slice containing all the live elements in the set
+func (s *WeakSet[T]) ToSlice() []*T {
+	var elems []*T
+	s.Each(func(elem *T) bool {
+		elems = append(elems, elem)
+		return true
+	})
+	return elems
+}
+
+// Prune removes the entries of elements that have been garbage collected and returns how many were removed.
+func (s *WeakSet[T]) Prune() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pruned := 0
+	for key, ptr := range s.set {
+		if ptr.Value() == nil {
+			delete(s.set, key)
+			pruned++
+		}
+	}
+	return pruned
+}
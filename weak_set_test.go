@@ -0,0 +1,68 @@
+package goset_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestWeakSet(t *testing.T) {
+	set := goset.NewWeakSet[TestType]()
+	kept := &TestType{ID: 1, Name: "One", Importance: 1}
+	dropped := &TestType{ID: 2, Name: "Two", Importance: 1}
+
+	assert.True(t, set.Add(kept, dropped))
+	assert.False(t, set.Add(kept))
+	assert.False(t, set.Add(nil))
+	assert.Equal(t, 2, set.Len())
+	assert.True(t, set.Contains(kept, dropped))
+	assert.False(t, set.Contains(&TestType{ID: 1, Name: "One", Importance: 1}))
+
+	dropped = nil
+	runtime.GC()
+
+	assert.True(t, set.Contains(kept))
+	assert.Equal(t, []*TestType{kept}, set.ToSlice())
+	for elem := range set.Iter() {
+		assert.Equal(t, kept, elem)
+	}
+	assert.Equal(t, 2, set.Len())
+
+	assert.Equal(t, 1, set.Prune())
+	assert.Equal(t, 1, set.Len())
+	assert.Zero(t, set.Prune())
+
+	set.Remove(kept)
+	assert.Zero(t, set.Len())
+	runtime.KeepAlive(kept)
+}
+
+func TestWeakSetWithKey(t *testing.T) {
+	set := goset.NewWeakSetWithKey(func(item *TestType) int { return item.ID })
+	first := &TestType{ID: 1, Name: "One", Importance: 1}
+	same := &TestType{ID: 1, Name: "Uno", Importance: 2}
+	dropped := &TestType{ID: 2, Name: "Two", Importance: 1}
+
+	assert.True(t, set.Add(first, dropped, nil))
+	assert.False(t, set.Add(same))
+	assert.True(t, set.Contains(same))
+	assert.Equal(t, 2, set.Len())
+
+	dropped = nil
+	runtime.GC()
+
+	assert.False(t, set.Contains(&TestType{ID: 2}))
+	assert.Equal(t, []*TestType{first}, set.ToSlice())
+	// a key whose element was collected can be taken by a new element
+	replacement := &TestType{ID: 2, Name: "Dos", Importance: 1}
+	assert.True(t, set.Add(replacement))
+	assert.Zero(t, set.Prune())
+
+	set.Remove(same)
+	assert.Equal(t, []*TestType{replacement}, set.ToSlice())
+	runtime.KeepAlive(first)
+	runtime.KeepAlive(replacement)
+}
@@ -0,0 +1,75 @@
+package goset
+
+import (
+	"sync"
+)
+
+// WindowSet is a thread-safe set that remembers only the most recent distinct elements added to it,
+// which makes it suitable for deduplicating a stream within a sliding window.
+// Once it holds size elements, adding a new distinct element evicts the oldest one.
+// Elements are ordered by when they were first added: re-adding an element still in the window
+// does not move it, so it is evicted as if it had not been added again.
+type WindowSet[T comparable] struct {
+	mu    sync.RWMutex
+	set   map[T]struct{}
+	order []T
+	head  int
+	size  int
+}
+
+// NewWindowSet returns an empty WindowSet holding at most size elements. A size below one is treated as one.
+func NewWindowSet[T comparable](size int) *WindowSet[T] {
+	size = max(size, 1)
+	return &WindowSet[T]{
+		set:   make(map[T]struct{}, size),
+		order: make([]T, 0, size),
+		size:  size,
+	}
+}
+
+// Add adds v to the window, evicting the oldest element if the window is full,
+// and returns a boolean indicating if v was newly seen within the window.
+func (s *WindowSet[T]) Add(v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.set[v]; ok {
+		return false
+	}
+	s.set[v] = struct{}{}
+	if len(s.order) < s.size {
+		s.order = append(s.order, v)
+		return true
+	}
+	delete(s.set, s.order[s.head])
+	s.order[s.head] = v
+	s.head = (s.head + 1) % s.size
+	return true
+}
+
+// Contains returns a boolean indicating if all the given elements are in the window.
+func (s *WindowSet[T]) Contains(v ...T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, val := range v {
+		if _, ok := s.set[val]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of elements in the window.
+func (s *WindowSet[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.set)
+}
+
+// ToSlice returns the elements in the window, oldest first.
+func (s *WindowSet[T]) ToSlice() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	elems := make([]T, 0, len(s.order))
+	elems = append(elems, s.order[s.head:]...)
+	return append(elems, s.order[:s.head]...)
+}
@@ -0,0 +1,36 @@
+package goset_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sfodje/goset"
+)
+
+func TestWindowSet(t *testing.T) {
+	set := goset.NewWindowSet[int](3)
+
+	assert.True(t, set.Add(1))
+	assert.True(t, set.Add(2))
+	assert.False(t, set.Add(1))
+	assert.True(t, set.Add(3))
+	assert.EqualValues(t, []int{1, 2, 3}, set.ToSlice())
+
+	// 1 was re-added but keeps its place, so it is the one evicted.
+	assert.True(t, set.Add(4))
+	assert.False(t, set.Contains(1))
+	assert.True(t, set.Contains(2, 3, 4))
+	assert.Equal(t, 3, set.Len())
+	assert.EqualValues(t, []int{2, 3, 4}, set.ToSlice())
+
+	assert.True(t, set.Add(1))
+	assert.True(t, set.Add(5))
+	assert.EqualValues(t, []int{4, 1, 5}, set.ToSlice())
+
+	single := goset.NewWindowSet[string](0)
+	assert.True(t, single.Add("a"))
+	assert.False(t, single.Add("a"))
+	assert.True(t, single.Add("b"))
+	assert.EqualValues(t, []string{"b"}, single.ToSlice())
+}